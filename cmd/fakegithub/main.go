@@ -0,0 +1,41 @@
+// fakegithub serves milestones, issues and pull requests from a fixtures file
+// under the same paths as the GitHub REST API, to run the release notes
+// extractor against synthetic data.
+//
+// Usage:
+//
+//	fakegithub --fixtures=fixtures.yaml [--addr=localhost:8080]
+//	GITHUB_API_URL=http://localhost:8080 release-notes
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/jespino/github-mm-release-notes/fakegithub"
+)
+
+func main() {
+	addr := flag.String("addr", "localhost:8080", "Address to listen on")
+	fixturesFile := flag.String("fixtures", "", "YAML or JSON file with the repositories, milestones, issues and pull requests to serve")
+	flag.Parse()
+
+	if *fixturesFile == "" {
+		fmt.Println("The --fixtures flag is required")
+		os.Exit(1)
+	}
+
+	fixtures, err := fakegithub.LoadFixtures(*fixturesFile)
+	if err != nil {
+		fmt.Printf("Error loading fixtures: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Serving a fake GitHub API on http://%s\n", *addr)
+	if err := http.ListenAndServe(*addr, fakegithub.NewHandler(fixtures)); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+)
+
+// clearComments makes the annotate command remove the comments of an entry
+var clearComments bool
+
+// Comment is a review comment of an editor on an entry of a release set,
+// e.g. "reword to mention SSO". Comments are never rendered in the notes.
+type Comment struct {
+	Text string    `yaml:"text"`
+	By   string    `yaml:"by,omitempty"`
+	At   time.Time `yaml:"at"`
+}
+
+// runAnnotate implements the annotate command, which adds a review comment
+// to the entry of a PR in a release set:
+//
+//	annotate --release-set=FILE owner/repo#123 "reword to mention SSO"
+//
+// With only the PR, its comments are removed with --clear, and without
+// arguments the comments of every entry are listed for the review.
+func runAnnotate() error {
+	if releaseSetFile == "" {
+		return fmt.Errorf("the --release-set flag with the release set file is required")
+	}
+
+	set, _, err := loadReleaseSet(releaseSetFile)
+	if err != nil {
+		return err
+	}
+
+	args := flag.Args()
+	switch {
+	case len(args) == 0:
+		printComments(set)
+		return nil
+	case len(args) == 1 && clearComments:
+	case len(args) == 2 && !clearComments:
+		if args[1] == "" {
+			return fmt.Errorf("the comment can't be empty")
+		}
+	default:
+		return fmt.Errorf("usage: annotate --release-set=FILE owner/repo#123 \"comment\", or annotate --release-set=FILE --clear owner/repo#123")
+	}
+
+	indexes, err := findReleaseEntries(set, args[:1])
+	if err != nil {
+		return err
+	}
+	entry := &set.Entries[indexes[0]]
+
+	if clearComments {
+		fmt.Printf("Removed %d comments of %s#%d\n", len(entry.Comments), entry.Repo, entry.Number)
+		entry.Comments = nil
+	} else {
+		entry.Comments = append(entry.Comments, Comment{Text: args[1], By: currentUser(), At: time.Now().UTC()})
+		fmt.Printf("Commented on %s#%d: %s\n", entry.Repo, entry.Number, args[1])
+	}
+
+	return writeReleaseSet(releaseSetFile, set)
+}
+
+// printComments lists the review comments of the entries of a release set
+func printComments(set ReleaseSet) {
+	commented := 0
+	for _, entry := range set.Entries {
+		if len(entry.Comments) == 0 {
+			continue
+		}
+		commented++
+
+		fmt.Printf("%s#%d: %s\n", entry.Repo, entry.Number, entry.Title)
+		fmt.Printf("Release Note: %s\n", entry.Note)
+		for _, comment := range entry.Comments {
+			by := ""
+			if comment.By != "" {
+				by = " " + comment.By
+			}
+			fmt.Printf("  - [%s%s] %s\n", comment.At.Format(time.DateOnly), by, comment.Text)
+		}
+		fmt.Println()
+	}
+
+	if commented == 0 {
+		fmt.Println("No comments in the release set.")
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"os"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/jespino/github-mm-release-notes/extract"
+	"github.com/jespino/github-mm-release-notes/internal/format"
+)
+
+// announcementTemplate renders the seed of the release announcement blog
+// post. It leaves PR numbers out and adds hooks reminding the writer to
+// phrase each entry around the benefit for users.
+var announcementTemplate = template.Must(template.New("announcement").Funcs(template.FuncMap{
+	"stats": announcementStats,
+}).Parse(`# What's new in {{.Milestone}}
+{{if .Highlights}}
+## Highlights
+{{range .Highlights}}
+### {{.Title}}
+
+{{.Note}}
+
+> Why it matters: _describe how this helps users in their daily work._
+{{end}}{{if $.ShowStats}}
+_Stats: {{stats .Highlights}}_
+{{end}}{{end}}{{if .Features}}
+## More new features
+
+{{range .Features}}- {{.Note}}
+{{end}}{{if $.ShowStats}}
+_Stats: {{stats .Features}}_
+{{end}}{{end}}{{if not (or .Highlights .Features)}}
+No new features in this release.
+{{end}}`))
+
+// announcementStats returns the stats of a section of the announcement
+func announcementStats(entries []AnnouncementEntry) format.SectionStats {
+	var notes []string
+	for _, entry := range entries {
+		notes = append(notes, entry.Title+" "+entry.Note)
+	}
+	return format.NotesStats(notes)
+}
+
+// AnnouncementEntry is a single feature in the announcement
+type AnnouncementEntry struct {
+	Title string
+	Note  string
+}
+
+// ticketPrefixRegexp matches the Jira ticket prefix of PR titles, e.g. "MM-12345: "
+var ticketPrefixRegexp = regexp.MustCompile(`^\[?[A-Z]+-\d+\]?\s*[:-]?\s*`)
+
+// renderAnnouncement prints the feature and highlight PRs through the
+// announcement template
+func renderAnnouncement(prs []PullRequest, milestoneTitle string) error {
+	data := struct {
+		Milestone  string
+		Highlights []AnnouncementEntry
+		Features   []AnnouncementEntry
+		ShowStats  bool
+	}{Milestone: milestoneDisplayName(milestoneTitle), ShowStats: showStats}
+
+	for _, pr := range prs {
+		isHighlight := hasAnyLabel(pr, splitList(highlightLabels))
+		if !isHighlight && !hasAnyLabel(pr, splitList(featureLabels)) {
+			continue
+		}
+
+		entry := AnnouncementEntry{
+			Title: strings.TrimSpace(ticketPrefixRegexp.ReplaceAllString(pr.Title, "")),
+			Note:  prReleaseNote(pr),
+		}
+		if !extract.HasReleaseNote(entry.Note) {
+			entry.Note = entry.Title
+		}
+
+		if isHighlight {
+			data.Highlights = append(data.Highlights, entry)
+		} else {
+			data.Features = append(data.Features, entry)
+		}
+	}
+
+	return announcementTemplate.Execute(os.Stdout, data)
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/jespino/github-mm-release-notes/extract"
+)
+
+// defaultTicketKeys are the project keys of the internal tickets stripped by
+// --anonymize when the configuration doesn't define them
+var defaultTicketKeys = []string{"MM"}
+
+var (
+	// handleRegexp matches @mentions of GitHub users and teams, keeping the
+	// character before them
+	handleRegexp = regexp.MustCompile(`(^|[^\w@/.])@[A-Za-z0-9](?:[A-Za-z0-9/-]*[A-Za-z0-9])?`)
+	// emptyParensRegexp matches the parentheses left empty by the stripping
+	emptyParensRegexp = regexp.MustCompile(`\(\s*\)`)
+	// spacesRegexp matches the runs of spaces left by the stripping
+	spacesRegexp = regexp.MustCompile(`[ \t]{2,}`)
+	// spaceBeforePunctuationRegexp matches a space left before punctuation
+	spaceBeforePunctuationRegexp = regexp.MustCompile(`[ \t]+([.,;:!?])`)
+)
+
+// anonymizer strips author handles and internal references, such as ticket
+// IDs, internal URLs and e-mail addresses, for --anonymize
+type anonymizer struct {
+	patterns []*regexp.Regexp
+}
+
+// newAnonymizer builds the anonymizer from the configuration
+func newAnonymizer() (*anonymizer, error) {
+	a := &anonymizer{patterns: []*regexp.Regexp{emailRegexp}}
+
+	for _, pattern := range config.InternalURLPatterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid internal URL pattern %q: %w", pattern, err)
+		}
+		a.patterns = append(a.patterns, re)
+	}
+
+	if len(config.TicketKeys) > 0 {
+		keys := make([]string, 0, len(config.TicketKeys))
+		for _, key := range config.TicketKeys {
+			keys = append(keys, regexp.QuoteMeta(key))
+		}
+		a.patterns = append(a.patterns, regexp.MustCompile(`\[?\b(?:`+strings.Join(keys, "|")+`)-\d+\b\]?:?`))
+	}
+
+	return a, nil
+}
+
+// strip removes the handles and internal references of a text
+func (a *anonymizer) strip(text string) string {
+	for _, re := range a.patterns {
+		text = re.ReplaceAllLiteralString(text, "")
+	}
+	return handleRegexp.ReplaceAllString(text, "$1")
+}
+
+// anonymize strips the handles and internal references of a note or title,
+// tidying up the spaces and punctuation left behind
+func (a *anonymizer) anonymize(text string) string {
+	text = a.strip(text)
+	text = emptyParensRegexp.ReplaceAllLiteralString(text, "")
+	text = spacesRegexp.ReplaceAllLiteralString(text, " ")
+	text = spaceBeforePunctuationRegexp.ReplaceAllString(text, "$1")
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// anonymizePRs strips in place the author handles and internal references
+// of the notes, titles and descriptions of the PRs, and the authors of the
+// hand-written entries, producing a customer-shareable variant of the notes
+func anonymizePRs(prs []PullRequest) error {
+	a, err := newAnonymizer()
+	if err != nil {
+		return err
+	}
+
+	for i := range prs {
+		if note := prReleaseNote(prs[i]); extract.HasReleaseNote(note) {
+			prs[i].ReleaseNote = a.anonymize(note)
+		}
+		prs[i].Title = a.anonymize(prs[i].Title)
+		// Descriptions keep their layout, e.g. for the test steps
+		prs[i].Body = a.strip(prs[i].Body)
+		prs[i].Author = ""
+		prs[i].User.Login = ""
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/jespino/github-mm-release-notes/github"
+)
+
+// apiRemediation detects the likely cause of authentication and authorization
+// errors of the GitHub API and returns the steps to fix them, or nil for
+// other errors. The errors of the other forges are left as they are, as
+// their tokens and status codes work differently.
+func apiRemediation(e *github.APIError) []string {
+	if !isGitHubURL(e.URL) {
+		return nil
+	}
+
+	message := strings.ToLower(e.Message())
+
+	switch {
+	case e.StatusCode == http.StatusUnauthorized:
+		if authToken == "" {
+			return []string{"This request requires authentication, provide a token with --token or GITHUB_TOKEN"}
+		}
+		return []string{
+			"The token is invalid, expired or revoked",
+			"Generate a new token in GitHub > Settings > Developer settings > Personal access tokens",
+			"Pass it with --token or GITHUB_TOKEN, and run the whoami command to check it",
+		}
+
+	case e.StatusCode == http.StatusForbidden && github.SSOAuthorizationURL(e.Header) != "":
+		return []string{
+			"The organization enforces SAML SSO and the token is not authorized for it",
+			"Authorize the token for the organization by visiting: " + github.SSOAuthorizationURL(e.Header),
+		}
+
+	case e.StatusCode == http.StatusForbidden && strings.Contains(message, "saml"):
+		return []string{
+			"The organization enforces SAML SSO and the token is not authorized for it",
+			"In GitHub > Settings > Developer settings > Personal access tokens, click \"Configure SSO\" next to the token and authorize the organization",
+		}
+
+	case e.StatusCode == http.StatusForbidden && strings.Contains(message, "not accessible by personal access token"):
+		return []string{
+			"The fine-grained token lacks a permission this request needs",
+			"Edit the token to include the repository and grant read access to Issues, Pull requests, Contents and Metadata",
+		}
+
+	case e.StatusCode == http.StatusForbidden && (e.Header.Get("X-RateLimit-Remaining") == "0" || strings.Contains(message, "rate limit")):
+		if authToken == "" {
+			return []string{"The unauthenticated rate limit is exhausted, provide a token with --token or GITHUB_TOKEN to get a higher limit"}
+		}
+		return []string{"The API rate limit is exhausted, wait until it resets (the whoami command shows when)"}
+
+	case e.StatusCode == http.StatusNotFound && strings.Contains(e.URL, "/repos/"):
+		// GitHub answers 404 rather than 403 for private repositories the
+		// caller can't see
+		if authToken == "" {
+			return []string{"The repository may be private, provide a token with access to it with --token or GITHUB_TOKEN"}
+		}
+		return []string{
+			"The resource doesn't exist or the token can't access it",
+			"Classic tokens need the repo scope to access private repositories",
+			"Fine-grained tokens must include the repository in their repository access",
+			"Run the whoami command to check which repositories the token can access",
+		}
+	}
+
+	return nil
+}
+
+// isGitHubURL reports whether an API request URL is for GitHub, rather than
+// for a configured repository hosted on another forge
+func isGitHubURL(requestURL string) bool {
+	for _, repo := range config.Repositories {
+		if requestURL == repo.URL() || strings.HasPrefix(requestURL, repo.URL()+"/") {
+			return isGitHubRepo(repo.URL())
+		}
+	}
+	return true
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jespino/github-mm-release-notes/github"
+	"golang.org/x/sync/errgroup"
+)
+
+// backfillFrom and backfillTo are the first and last versions, inclusive, of
+// the milestones the backfill command extracts into release sets in
+// backfillDir
+var (
+	backfillFrom string
+	backfillTo   string
+	backfillDir  string
+)
+
+// parallelMilestones is how many milestones are processed at the same time
+// by the backfill command and when generating the notes of several
+// milestones, within the rate limit left
+var parallelMilestones int
+
+// inVersionRange reports whether a milestone title is within the from and to
+// versions, either of which can be empty. A version without a patch number
+// includes its patch releases, e.g. v10.2 includes v10.2.1.
+func inVersionRange(title string, from string, to string) bool {
+	if from != "" && compareVersionTitles(title, from) < 0 {
+		return false
+	}
+	if to != "" && compareVersionTitles(title, to) > 0 && !strings.HasPrefix(title, to+".") {
+		return false
+	}
+	return true
+}
+
+// getClosedMilestones returns the closed milestones of the repositories
+// within the version range, unified by title and sorted by version. Listing
+// closed milestones is only supported on GitHub.
+func getClosedMilestones(repoURLs []string, from string, to string) []UnifiedMilestone {
+	byTitle := make(map[string]*UnifiedMilestone)
+	for _, repoURL := range repoURLs {
+		if !isGitHubRepo(repoURL) {
+			logger.Warn("Skipping repository, the feature is only supported on GitHub", "repo", repoNameFromURL(repoURL), "feature", "backfill")
+			continue
+		}
+
+		milestones, err := github.GetAllPages[Milestone](apiClient, repoURL+"/milestones?state=closed")
+		if err != nil {
+			logger.Warn("Skipping repository, its milestones can't be listed", "repo", repoNameFromURL(repoURL), "error", err)
+			continue
+		}
+		for _, milestone := range excludeMilestones(milestones) {
+			if !inVersionRange(milestone.Title, from, to) {
+				continue
+			}
+			milestone.RepoURL = repoURL
+			if byTitle[milestone.Title] == nil {
+				byTitle[milestone.Title] = &UnifiedMilestone{Title: milestone.Title}
+			}
+			byTitle[milestone.Title].Milestones = append(byTitle[milestone.Title].Milestones, milestone)
+		}
+	}
+
+	var unified []UnifiedMilestone
+	for _, um := range byTitle {
+		unified = append(unified, *um)
+	}
+	sort.Slice(unified, func(i, j int) bool {
+		return compareVersionTitles(unified[i].Title, unified[j].Title) < 0
+	})
+	return unified
+}
+
+// backfillPath returns the release set file of a milestone in the backfill
+// directory
+func backfillPath(milestoneTitle string) string {
+	return filepath.Join(backfillDir, unsafeFileNameRegexp.ReplaceAllString(milestoneTitle, "_")+".yaml")
+}
+
+// runBackfill implements the backfill command, which extracts the entries of
+// every closed milestone between --from and --to into a release set per
+// milestone in --backfill-dir, building the historical record in one run.
+// The milestones are processed by --parallel workers, a failure leaving the
+// rest unaffected, and the milestones already in the directory are skipped,
+// so an interrupted or failed run resumes where it stopped. With
+// --export-entries, the entries are exported for the docs site too.
+func runBackfill() error {
+	if backfillFrom == "" && backfillTo == "" {
+		return fmt.Errorf("the --from or --to flag with the range of versions is required")
+	}
+
+	repoURLs := allRepoURLs
+	repoName := "all repositories"
+	if repoFlag != "" {
+		option, ok := findRepoOption(repoOptions(), repoFlag)
+		if !ok {
+			return fmt.Errorf("invalid repository %q", repoFlag)
+		}
+		repoURLs = repositoryURLs(option.Repos)
+		repoName = option.Name
+	}
+
+	milestones := getClosedMilestones(repoURLs, backfillFrom, backfillTo)
+	if len(milestones) == 0 {
+		return fmt.Errorf("no closed milestones found between %q and %q", backfillFrom, backfillTo)
+	}
+
+	if err := os.MkdirAll(backfillDir, 0755); err != nil {
+		return err
+	}
+
+	// The milestones already backfilled don't count against the rate limit
+	var pending []UnifiedMilestone
+	skipped := 0
+	for _, um := range milestones {
+		if _, err := os.Stat(backfillPath(um.Title)); err == nil {
+			skipped++
+			continue
+		}
+		pending = append(pending, um)
+	}
+
+	fmt.Printf("Backfilling %d milestones into %s, %d were already done\n", len(pending), backfillDir, skipped)
+	requestsPerMilestone := 0
+	for _, um := range pending {
+		requestsPerMilestone = max(requestsPerMilestone, estimatedRequests(um.Milestones))
+	}
+	workers := plannedWorkers(parallelMilestones, requestsPerMilestone)
+
+	// Each milestone is processed on its own, a failure doesn't stop the rest
+	start := time.Now()
+	var mu sync.Mutex
+	done := 0
+	var failed []string
+	var g errgroup.Group
+	g.SetLimit(workers)
+	for _, um := range pending {
+		g.Go(func() error {
+			path := backfillPath(um.Title)
+			count, err := backfillMilestone(um, repoName, path)
+
+			mu.Lock()
+			defer mu.Unlock()
+			done++
+			if err != nil {
+				failed = append(failed, um.Title)
+				logger.Error("Error backfilling milestone", "milestone", um.Title, "progress", fmt.Sprintf("%d/%d", done, len(pending)), "error", err)
+				return nil
+			}
+			fmt.Printf("[%d/%d] %s: %d entries written to %s (%s elapsed)\n", done, len(pending), um.Title, count, path, time.Since(start).Round(time.Second))
+			return nil
+		})
+	}
+	g.Wait()
+
+	fmt.Printf("\nBackfilled %d milestones, %d were already done, %d failed\n", len(pending)-len(failed), skipped, len(failed))
+	if len(failed) > 0 {
+		sort.Slice(failed, func(i, j int) bool {
+			return compareVersionTitles(failed[i], failed[j]) < 0
+		})
+		return fmt.Errorf("error backfilling %s, re-run to resume", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// backfillMilestone extracts the entries of a milestone into a release set
+// at path, returning the number of entries. It runs in its own goroutine, a
+// panic fails the milestone like any other error.
+func backfillMilestone(um UnifiedMilestone, repoName string, path string) (count int, err error) {
+	defer recoverGoroutine(&err)
+
+	prs, errs := fetchPRs(um.Milestones)
+	for i, err := range errs {
+		if err != nil {
+			return 0, fmt.Errorf("error getting PRs from %s: %w", repoNameFromURL(um.Milestones[i].RepoURL), err)
+		}
+	}
+
+	if commitNotes {
+		if err := fetchCommitNotes(prs); err != nil {
+			return 0, err
+		}
+	}
+	prs, _ = separateReverts(prs)
+	prs, _ = separateNoneNotes(prs)
+	prs = collapseFollowUps(prs)
+	prs, err = applyDedup(prs)
+	if err != nil {
+		return 0, err
+	}
+
+	if exportDir != "" {
+		if _, err := exportEntries(exportDir, prs, um.Title); err != nil {
+			return 0, fmt.Errorf("error exporting entries: %w", err)
+		}
+	}
+
+	// Write the release set last, and atomically, as its presence marks the
+	// milestone as done
+	tmpPath := path + ".tmp"
+	if err := writeReleaseSet(tmpPath, buildReleaseSet(prs, um.Title, milestoneHeader(um.Title), repoName)); err != nil {
+		return 0, err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return 0, err
+	}
+
+	return len(prs), nil
+}
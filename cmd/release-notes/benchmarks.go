@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	// benchmarkBlockRegexp matches code blocks with pasted benchmark results,
+	// e.g. ```benchstat
+	benchmarkBlockRegexp = regexp.MustCompile("(?s)```\\s*(?:benchstat|benchmarks?)\\s*\n(.*?)\n\\s*```")
+	// benchmarkDeltaRegexp matches a signed percentage, as in the delta
+	// column of benchstat. Unsigned variations like "± 2%" don't match.
+	benchmarkDeltaRegexp = regexp.MustCompile(`\s([+-]\d+(?:\.\d+)?%)`)
+)
+
+// extractBenchmarkDeltas returns the benchmark deltas pasted in a PR
+// description as "name: delta", taken from benchmark code blocks and from
+// lines of `go test -bench` output anywhere in the body
+func extractBenchmarkDeltas(body string) []string {
+	var lines []string
+	for _, block := range benchmarkBlockRegexp.FindAllStringSubmatch(body, -1) {
+		lines = append(lines, strings.Split(block[1], "\n")...)
+	}
+	for _, line := range strings.Split(body, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "Benchmark") {
+			lines = append(lines, line)
+		}
+	}
+
+	var deltas []string
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		delta := benchmarkDeltaRegexp.FindStringSubmatch(line)
+		if len(fields) == 0 || delta == nil {
+			continue
+		}
+
+		entry := fmt.Sprintf("%s: %s", fields[0], delta[1])
+		if !containsString(deltas, entry) {
+			deltas = append(deltas, entry)
+		}
+	}
+
+	return deltas
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// Bundle holds the raw data fetched from GitHub for a milestone, so the notes
+// can be rendered on a machine without GitHub access
+type Bundle struct {
+	SchemaVersion int               `json:"schema_version"`
+	Milestone     string            `json:"milestone"`
+	RepoName      string            `json:"repo_name"`
+	CreatedAt     time.Time         `json:"created_at"`
+	Milestones    []BundleMilestone `json:"milestones"`
+	PullRequests  []BundlePR        `json:"pull_requests"`
+}
+
+// BundleMilestone is a milestone of one of the repositories
+type BundleMilestone struct {
+	Repo string `json:"repo"`
+	Milestone
+}
+
+// BundlePR is a PR along with the details fetched on demand, which are not
+// serialized with the PR itself
+type BundlePR struct {
+	Repo string `json:"repo"`
+	PullRequest
+	Files     []string  `json:"files"`
+	Additions int       `json:"additions"`
+	Deletions int       `json:"deletions"`
+	MergedAt  time.Time `json:"merged_at"`
+}
+
+// bundleFile returns the path of the bundle file, defaulting to one named
+// after the milestone
+func bundleFile(milestoneTitle string) string {
+	if bundlePath != "" {
+		return bundlePath
+	}
+	return strings.TrimSuffix(snapshotFileName(milestoneTitle, 0), "-rc0.json") + ".bundle.json.gz"
+}
+
+// getMilestonePRs returns the milestones titled milestoneTitle in the given
+// repositories and their PRs with release notes. Repositories whose
+// milestones can't be listed, e.g. private ones, are skipped with a warning,
+// and so are the ones excluded from the milestone by the configuration.
+func getMilestonePRs(milestoneTitle string, repoURLs []string) ([]Milestone, []PullRequest, error) {
+	milestoneSets := make([][]Milestone, len(repoURLs))
+
+	var g errgroup.Group
+	g.SetLimit(maxConcurrentRequests)
+	for i, repoURL := range repoURLs {
+		g.Go(func() (err error) {
+			defer recoverGoroutine(&err)
+
+			repo := findRepository(repoURL)
+			milestones, err := repo.forge().Milestones(repo)
+			if err != nil {
+				logger.Warn("Skipping repository, its milestones can't be listed", "repo", repoNameFromURL(repoURL), "error", err)
+				return nil
+			}
+			for _, milestone := range milestones {
+				if milestone.Title == milestoneTitle {
+					milestone.RepoURL = repoURL
+					milestoneSets[i] = append(milestoneSets[i], milestone)
+				}
+			}
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, nil, err
+	}
+
+	var targetMilestones []Milestone
+	for _, milestones := range milestoneSets {
+		targetMilestones = append(targetMilestones, milestones...)
+	}
+	targetMilestones = excludeMilestones(targetMilestones)
+
+	prs, errs := fetchPRs(targetMilestones)
+	for i, err := range errs {
+		if err != nil {
+			return nil, nil, fmt.Errorf("error getting PRs from %s: %w", repoNameFromURL(targetMilestones[i].RepoURL), err)
+		}
+	}
+
+	return targetMilestones, prs, nil
+}
+
+// runBundleExport implements the bundle export command, which fetches the
+// milestone from every repository, along with the changed files and diff
+// stats of its PRs, and writes it all to a bundle file
+func runBundleExport() error {
+	if milestoneFlag == "" {
+		return fmt.Errorf("the --milestone flag is required")
+	}
+
+	targetMilestones, prs, err := getMilestonePRs(milestoneFlag, allRepoURLs)
+	if err != nil {
+		return err
+	}
+	if len(targetMilestones) == 0 {
+		return fmt.Errorf("no open milestone %s found", milestoneFlag)
+	}
+	if err := fetchPRFiles(prs); err != nil {
+		return err
+	}
+	if err := fetchPRDetails(prs); err != nil {
+		return err
+	}
+
+	bundle := Bundle{Milestone: milestoneFlag, RepoName: "all repositories", CreatedAt: time.Now().UTC()}
+	if len(targetMilestones) == 1 {
+		bundle.RepoName = repoNameFromURL(targetMilestones[0].RepoURL)
+	}
+	for _, milestone := range targetMilestones {
+		bundle.Milestones = append(bundle.Milestones, BundleMilestone{Repo: repoNameFromURL(milestone.RepoURL), Milestone: milestone})
+	}
+	for _, pr := range prs {
+		bundle.PullRequests = append(bundle.PullRequests, BundlePR{
+			Repo:        repoNameFromURL(pr.RepoURL),
+			PullRequest: pr,
+			Files:       pr.Files,
+			Additions:   pr.Additions,
+			Deletions:   pr.Deletions,
+			MergedAt:    pr.MergedAt,
+		})
+	}
+
+	path := bundleFile(milestoneFlag)
+	if err := writeBundle(path, &bundle); err != nil {
+		return err
+	}
+
+	logger.Info("Bundle written", "file", path, "prs", len(prs), "repos", len(targetMilestones))
+	return nil
+}
+
+// writeBundle writes a bundle file with the current schema version
+func writeBundle(path string, bundle *Bundle) error {
+	bundle.SchemaVersion = bundleSchema.Version
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := gzip.NewWriter(file)
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(bundle); err != nil {
+		return err
+	}
+
+	return writer.Close()
+}
+
+// loadBundle reads a bundle file written by the bundle export command,
+// migrating it to the current schema, and reports whether it was migrated
+func loadBundle(path string) (*Bundle, bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, false, err
+	}
+	defer file.Close()
+
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid bundle %s: %w", path, err)
+	}
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid bundle %s: %w", path, err)
+	}
+
+	var bundle Bundle
+	migrated, err := bundleSchema.decodeJSON(data, &bundle)
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid bundle %s: %w", path, err)
+	}
+
+	return &bundle, migrated, nil
+}
+
+// runBundleImport implements the bundle import command, which renders the
+// release notes from a bundle file like the interactive mode does, without
+// GitHub access. Options needing data not in the bundle, like the known
+// issues, still query GitHub.
+func runBundleImport() error {
+	if bundlePath == "" {
+		return fmt.Errorf("the --bundle flag with the bundle file is required")
+	}
+
+	bundle, _, err := loadBundle(bundlePath)
+	if err != nil {
+		return err
+	}
+
+	var targetMilestones []Milestone
+	for _, milestone := range bundle.Milestones {
+		milestone.Milestone.RepoURL = repoURLFromName(milestone.Repo)
+		targetMilestones = append(targetMilestones, milestone.Milestone)
+	}
+
+	var prs []PullRequest
+	for _, bundlePR := range bundle.PullRequests {
+		pr := bundlePR.PullRequest
+		pr.RepoURL = repoURLFromName(bundlePR.Repo)
+		pr.Files = bundlePR.Files
+		if pr.Files == nil {
+			pr.Files = []string{}
+		}
+		pr.Additions = bundlePR.Additions
+		pr.Deletions = bundlePR.Deletions
+		pr.MergedAt = bundlePR.MergedAt
+		pr.detailsFetched = true
+		prs = append(prs, pr)
+	}
+
+	fmt.Printf("Rendering milestone %s from the bundle created at %s\n\n", bundle.Milestone, bundle.CreatedAt.Format(time.RFC3339))
+	return generateReleaseNotes(prs, bundle.Milestone, targetMilestones, bundle.RepoName)
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+)
+
+var (
+	// noCache disables the cache of the GitHub API responses
+	noCache bool
+	// cacheDir overrides the directory of the cache
+	cacheDir string
+)
+
+// responseCacheDir returns the directory where the responses are cached
+func responseCacheDir() (string, error) {
+	if cacheDir != "" {
+		return cacheDir, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, ".release-notes-extractor", "cache"), nil
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/jespino/github-mm-release-notes/github"
+)
+
+// TimelineEvent is an event of the timeline of an issue or PR
+type TimelineEvent struct {
+	Event     string    `json:"event"`
+	CreatedAt time.Time `json:"created_at"`
+	Milestone *struct {
+		Title string `json:"title"`
+	} `json:"milestone"`
+	Label *struct {
+		Name string `json:"name"`
+	} `json:"label"`
+}
+
+// CarriedOverBug is a bug moved out of the milestone
+type CarriedOverBug struct {
+	Issue Issue
+	To    string // Title of the milestone it was moved to, if any
+}
+
+// getTimeline returns the timeline events of an issue or PR
+func getTimeline(repoURL string, number int) ([]TimelineEvent, error) {
+	return github.GetAllPages[TimelineEvent](apiClient, fmt.Sprintf("%s/issues/%d/timeline?per_page=100", repoURL, number))
+}
+
+// getCarriedOverBugs returns the bugs that were assigned to the milestones
+// and later moved out of them, found through the demilestoned events of the
+// bugs updated since each milestone was created
+func getCarriedOverBugs(milestones []Milestone) ([]CarriedOverBug, error) {
+	var bugs []CarriedOverBug
+	seen := make(map[string]bool)
+
+	for _, milestone := range milestones {
+		for _, label := range config.BugLabels {
+			query := url.Values{}
+			query.Set("state", "all")
+			query.Set("labels", label)
+			query.Set("per_page", "100")
+			if !milestone.CreatedAt.IsZero() {
+				query.Set("since", milestone.CreatedAt.Format(time.RFC3339))
+			}
+
+			issues, err := github.GetAllPages[Issue](apiClient, fmt.Sprintf("%s/issues?%s", milestone.RepoURL, query.Encode()))
+			if err != nil {
+				return nil, err
+			}
+
+			for _, issue := range issues {
+				key := fmt.Sprintf("%s#%d", milestone.RepoURL, issue.Number)
+				if issue.PullRequest != nil || seen[key] {
+					continue
+				}
+				if issue.Milestone != nil && issue.Milestone.Title == milestone.Title {
+					continue
+				}
+				issue.RepoURL = milestone.RepoURL
+
+				events, err := getTimeline(issue.RepoURL, issue.Number)
+				if err != nil {
+					return nil, err
+				}
+
+				if moved, to := movedOutOf(events, milestone.Title); moved {
+					seen[key] = true
+					bugs = append(bugs, CarriedOverBug{Issue: issue, To: to})
+				}
+			}
+		}
+	}
+
+	return bugs, nil
+}
+
+// movedOutOf reports whether the timeline has the issue removed from the
+// milestone, and the title of the milestone it was assigned to afterwards
+func movedOutOf(events []TimelineEvent, milestoneTitle string) (bool, string) {
+	moved := false
+	to := ""
+	for _, event := range events {
+		if event.Milestone == nil {
+			continue
+		}
+
+		switch {
+		case event.Event == "demilestoned" && event.Milestone.Title == milestoneTitle:
+			moved = true
+			to = ""
+		case event.Event == "milestoned" && moved:
+			to = event.Milestone.Title
+		}
+	}
+
+	return moved, to
+}
+
+// printCarriedOverBugs prints the report of the bugs deferred out of the milestone
+func printCarriedOverBugs(bugs []CarriedOverBug) {
+	if len(bugs) == 0 {
+		return
+	}
+
+	fmt.Println("Deferred bugs:")
+	fmt.Println()
+	for _, bug := range bugs {
+		to := "no milestone"
+		if bug.To != "" {
+			to = bug.To
+		}
+		fmt.Printf("- %s (%s#%d), moved to %s\n", bug.Issue.Title, repoNameFromURL(bug.Issue.RepoURL), bug.Issue.Number, to)
+	}
+	fmt.Println()
+}
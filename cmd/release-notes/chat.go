@@ -0,0 +1,19 @@
+package main
+
+// postThread posts the messages of a chat target in a thread: the first one
+// to the channel and the others as replies to it, so long notes don't flood
+// the channel. post sends a message, as a reply to the post rootID when not
+// empty, and returns the ID of the new post.
+func postThread(messages []string, post func(message string, rootID string) (string, error)) error {
+	rootID := ""
+	for _, message := range messages {
+		id, err := post(message, rootID)
+		if err != nil {
+			return err
+		}
+		if rootID == "" {
+			rootID = id
+		}
+	}
+	return nil
+}
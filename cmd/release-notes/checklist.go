@@ -0,0 +1,42 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jespino/github-mm-release-notes/extract"
+)
+
+// writeChecklist writes a Markdown QA checklist with one checkbox per
+// user-facing change, grouped by area, ready to be pasted into the release
+// testing issue. PRs must have their files fetched beforehand.
+func writeChecklist(path string, prs []PullRequest, milestoneTitle string) error {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# QA checklist for %s\n", milestoneTitle)
+
+	for _, group := range groupPRsByArea(prs) {
+		var items []string
+		for _, pr := range group.PRs {
+			releaseNote := releaseNoteForPR(pr)
+			if !extract.HasReleaseNote(releaseNote) {
+				continue
+			}
+			// Keep each checkbox on a single line
+			releaseNote = strings.Join(strings.Fields(releaseNote), " ")
+			items = append(items, fmt.Sprintf("- [ ] %s (%s)", releaseNote, prRef(pr)))
+		}
+
+		if len(items) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(&buf, "\n## %s\n\n", group.Area)
+		for _, item := range items {
+			fmt.Fprintln(&buf, item)
+		}
+	}
+
+	return os.WriteFile(path, buf.Bytes(), 0644)
+}
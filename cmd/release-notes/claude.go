@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/anthropics/anthropic-sdk-go/option"
+)
+
+// changeLogTypeFor returns the type of changelog Claude is asked to write for
+// the notes of a repository
+func changeLogTypeFor(repoName string) string {
+	if changeLogType := findRepository(repoURLFromName(repoName)).ChangelogType; changeLogType != "" {
+		return changeLogType
+	}
+	return "mattermost"
+}
+
+// formatReleaseNotesWithClaude sends the release notes to Anthropic's Claude API
+// and returns the formatted version organized by categories
+func formatReleaseNotesWithClaude(apiKey string, releaseNotes string, milestoneName string, changeLogType string) (string, error) {
+	client := anthropic.NewClient(option.WithAPIKey(apiKey))
+
+	// Prepare the prompt for Claude
+	prompt := fmt.Sprintf(`Here are the raw release notes for Mattermost milestone %s:
+
+%s
+
+Please remove the PR numbers and ticket titles. Then, please turn each release note into clear sentences and organize them into categories. The categories are: 
+- Compatibility
+- Important Upgrade Notes
+- User Interface (UI) Improvements
+- Administration Improvements 
+- Performance Improvements
+- Bug Fixes
+- config.json Changes
+- API Changes
+- Websocket Event Changes
+- Database Changes
+- Go Version Updates
+- Breaking Changes
+
+Only include categories that have at least one entry. Format your response as markdown.`, milestoneName, releaseNotes)
+
+	if changeLogType == "mobile" {
+		prompt = fmt.Sprintf(`Here are the raw release notes for Mattermost milestone %s:
+
+%s
+
+Please remove the PR numbers and ticket titles. Then, please turn each release note into clear sentences and organize them into categories. The categories are:
+	- Compatibility
+	- Important Upgrade Notes
+	- Improvements
+	- Bug Fixes
+
+Only include categories that have at least one entry. Format your response as markdown.`, milestoneName, releaseNotes)
+	}
+
+	if changeLogType == "desktop" {
+		prompt = fmt.Sprintf(`Here are the raw release notes for Mattermost milestone %s:
+
+%s
+
+Please remove the PR numbers and ticket titles. Then, please turn each release note into clear sentences and organize them into categories. The categories are:
+	- Compatibility
+	- Improvements
+	- Architectural Changes
+	- Bug Fixes.
+
+Only include categories that have at least one entry. Format your response as markdown.`, milestoneName, releaseNotes)
+	}
+
+	// Send the request to Claude
+	resp, err := client.Messages.New(context.Background(), anthropic.MessageNewParams{
+		Model:     "claude-3-opus-20240229",
+		MaxTokens: 4000,
+		System: []anthropic.TextBlockParam{
+			{Text: "You organize release notes into categories like: Compatibility, Important Upgrade Notes, UI Improvements, etc."},
+		},
+		Messages: []anthropic.MessageParam{
+			{
+				Role: anthropic.MessageParamRoleUser,
+				Content: []anthropic.ContentBlockParamUnion{
+					{
+						OfRequestTextBlock: &anthropic.TextBlockParam{
+							Text: prompt,
+						},
+					},
+				},
+			},
+		},
+	})
+
+	if err != nil {
+		return "", fmt.Errorf("error calling Claude API: %w", err)
+	}
+
+	// Extract the response text
+	if len(resp.Content) == 0 {
+		return "", fmt.Errorf("received empty response from Claude API")
+	}
+
+	// Extract text from the response
+	var responseText string
+	for _, block := range resp.Content {
+		if textBlock, ok := block.AsAny().(anthropic.TextBlock); ok {
+			responseText += textBlock.Text
+		}
+	}
+
+	if responseText == "" {
+		return "", fmt.Errorf("could not find text response from Claude API")
+	}
+
+	return responseText, nil
+}
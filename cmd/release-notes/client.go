@@ -0,0 +1,48 @@
+package main
+
+import (
+	"runtime/debug"
+
+	"github.com/jespino/github-mm-release-notes/github"
+)
+
+// apiClient makes the requests to the GitHub API, and to the APIs of the
+// other forges of the configured repositories. It is set up by main once
+// the flags and the configuration are loaded.
+var apiClient = newAPIClient()
+
+// newAPIClient returns a client for the API URL, token, cache and
+// configuration given with the flags
+func newAPIClient() *github.Client {
+	cacheDir := ""
+	if !noCache {
+		if dir, err := responseCacheDir(); err == nil {
+			cacheDir = dir
+		}
+	}
+
+	return github.NewClient(authToken, github.Config{
+		BaseURL:     apiBaseURL,
+		UserAgent:   userAgent(),
+		Headers:     config.HTTPHeaders,
+		Retry:       config.Retry,
+		CacheDir:    cacheDir,
+		Auth:        authHeader,
+		Remediation: apiRemediation,
+	}, logger)
+}
+
+// userAgent returns the User-Agent sent to the GitHub API, identifying the
+// tool and its version unless overridden in the config
+func userAgent() string {
+	if config.UserAgent != "" {
+		return config.UserAgent
+	}
+
+	version := "dev"
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		version = info.Main.Version
+	}
+
+	return "github-mm-release-notes/" + version
+}
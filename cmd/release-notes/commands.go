@@ -0,0 +1,50 @@
+package main
+
+import "fmt"
+
+// runCommand runs one of the non-interactive commands given as the first
+// argument
+func runCommand(command string) error {
+	switch command {
+	case "publish":
+		return runPublish()
+	case "assemble-ga":
+		return runAssembleGA()
+	case "whoami":
+		return runWhoami()
+	case "doctor":
+		return runDoctor()
+	case "release":
+		return runRelease()
+	case "render":
+		return runRender()
+	case "watch":
+		return runWatch()
+	case "refresh":
+		return runRefresh()
+	case "exclude":
+		return runExclude()
+	case "restore":
+		return runRestore()
+	case "annotate":
+		return runAnnotate()
+	case "backfill":
+		return runBackfill()
+	case "import-changelog":
+		return runImportChangelog()
+	case "upgrade-notes":
+		return runUpgradeNotes()
+	case "diff":
+		return runDiff()
+	case "lint":
+		return runLint()
+	case "migrate":
+		return runMigrate()
+	case "bundle export":
+		return runBundleExport()
+	case "bundle import":
+		return runBundleImport()
+	default:
+		return fmt.Errorf("unknown command %q", command)
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jespino/github-mm-release-notes/extract"
+)
+
+// getMergeCommitMessage returns the message of the commit a PR was merged
+// with, or an empty string if it wasn't merged
+func getMergeCommitMessage(repoURL string, number int) (string, error) {
+	var pull struct {
+		MergeCommitSHA string     `json:"merge_commit_sha"`
+		MergedAt       *time.Time `json:"merged_at"`
+	}
+	if err := apiClient.GetJSON(fmt.Sprintf("%s/pulls/%d", repoURL, number), &pull); err != nil {
+		return "", err
+	}
+	// Open PRs have the SHA of a test merge commit
+	if pull.MergedAt == nil || pull.MergeCommitSHA == "" {
+		return "", nil
+	}
+
+	var commit struct {
+		Commit struct {
+			Message string `json:"message"`
+		} `json:"commit"`
+	}
+	if err := apiClient.GetJSON(repoURL+"/commits/"+pull.MergeCommitSHA, &commit); err != nil {
+		return "", err
+	}
+	return commit.Commit.Message, nil
+}
+
+// commitTrailerNote returns the Release-Note: trailer of the merge commit of
+// a PR, or an empty string if there is none
+func commitTrailerNote(pr PullRequest) (string, error) {
+	message, err := getMergeCommitMessage(pr.RepoURL, pr.Number)
+	if err != nil {
+		return "", fmt.Errorf("error getting the merge commit of PR #%d: %w", pr.Number, err)
+	}
+	return extract.CommitTrailer(message), nil
+}
+
+// fetchCommitNotes fills in place the release notes of the PRs without one
+// in their description from the Release-Note: trailer of their merge commit,
+// for repositories that squash-merge and keep the notes in the commit
+// messages. PRs whose description says NONE are left alone.
+func fetchCommitNotes(prs []PullRequest) error {
+	found := 0
+	for i := range prs {
+		pr := prs[i]
+		if pr.Manual || pr.ReleaseNote != "" || !isGitHubRepo(pr.RepoURL) {
+			continue
+		}
+		note := prReleaseNote(pr)
+		if extract.HasReleaseNote(note) || extract.IsNone(note) {
+			continue
+		}
+
+		trailer, err := commitTrailerNote(pr)
+		if err != nil {
+			return err
+		}
+		if trailer != "" {
+			prs[i].ReleaseNote = trailer
+			prs[i].CommitNote = true
+			found++
+		}
+	}
+
+	if found > 0 {
+		logger.Info("Release notes taken from merge commit trailers", "count", found)
+	}
+	return nil
+}
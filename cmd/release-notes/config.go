@@ -0,0 +1,244 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jespino/github-mm-release-notes/github"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultConfigFile is the configuration file loaded when --config is not
+// given, relative to the home directory
+const defaultConfigFile = ".release-notes-extractor.yaml"
+
+// Config holds the settings loaded from the configuration file
+type Config struct {
+	// DeveloperSections replaces the default developer sections when set
+	DeveloperSections []Section `yaml:"developer_sections"`
+	// PerformanceLabels mark the PRs listed in the performance section
+	PerformanceLabels []string `yaml:"performance_labels"`
+	// AccessibilityLabels mark the PRs listed in the accessibility section
+	AccessibilityLabels []string `yaml:"accessibility_labels"`
+	// BugLabels mark the open issues of a milestone listed as known issues
+	BugLabels []string `yaml:"bug_labels"`
+	// BreakingLabels mark the PRs listed as breaking changes
+	BreakingLabels []string `yaml:"breaking_labels"`
+	// DeprecationLabels mark the PRs listed as deprecations
+	DeprecationLabels []string `yaml:"deprecation_labels"`
+	// KnownIssueLabels mark the open issues always listed as known issues
+	KnownIssueLabels []string `yaml:"known_issue_labels"`
+	// UserAgent replaces the default User-Agent of the GitHub API requests
+	UserAgent string `yaml:"user_agent"`
+	// HTTPHeaders are extra headers sent with every GitHub API request, as
+	// required by some proxies and GitHub Enterprise setups
+	HTTPHeaders map[string]string `yaml:"http_headers"`
+	// NoteTemplates rephrase the notes of the PRs with some labels, the first
+	// matching template is used
+	NoteTemplates []NoteTemplate `yaml:"note_templates"`
+	// Terminology is the dictionary of preferred terms the notes are
+	// checked against by the lint command
+	Terminology []Term `yaml:"terminology"`
+	// ProfanityWords are flagged in the notes before publishing
+	ProfanityWords []string `yaml:"profanity_words"`
+	// InternalURLPatterns are regular expressions matching internal ticket
+	// URLs, flagged in the notes before publishing
+	InternalURLPatterns []string `yaml:"internal_url_patterns"`
+	// CategoryRules guess the type of change of the PRs without a type
+	// label or note prefix, the first matching rule is used
+	CategoryRules []CategoryRule `yaml:"category_rules"`
+	// TicketKeys are the project keys of the internal tickets, e.g. MM for
+	// MM-12345, stripped by --anonymize
+	TicketKeys []string `yaml:"ticket_keys"`
+	// MilestoneNames map milestone titles to the public version names used
+	// in the headers and file names
+	MilestoneNames []MilestoneName `yaml:"milestone_names"`
+	// MilestoneRules exclude repositories from the notes of some milestones
+	MilestoneRules []MilestoneRule `yaml:"milestone_rules"`
+	// Repositories replaces the default Mattermost repositories when set
+	Repositories []Repository `yaml:"repositories"`
+	// RepoGroups are named sets of repositories, e.g. the mobile and desktop
+	// apps, shown in the repository menu and selected with --group
+	RepoGroups map[string][]string `yaml:"repo_groups"`
+	// SKUs are the editions of the product some changes only apply to,
+	// selected with --sku
+	SKUs []SKU `yaml:"skus"`
+	// Delivery configures the Cloud and self-hosted variants of the notes
+	// rendered with --split-delivery
+	Delivery Delivery `yaml:"delivery"`
+	// Retry configures the retries of the GitHub API requests failing with
+	// transient errors
+	Retry github.RetryPolicy `yaml:"retry"`
+	// ChatLinkShortener shortens the links of the notes posted to
+	// Mattermost with --short-links
+	ChatLinkShortener LinkShortener `yaml:"chat_link_shortener"`
+}
+
+// config is the loaded configuration
+var config = defaultConfig()
+
+// defaultConfig returns the configuration used when there is no
+// configuration file
+func defaultConfig() Config {
+	return Config{
+		DeveloperSections:   []Section{apiChangesSection, eventChangesSection},
+		PerformanceLabels:   []string{"performance"},
+		AccessibilityLabels: []string{"accessibility"},
+		BugLabels:           []string{"bug", "kind/bug"},
+		KnownIssueLabels:    []string{"known-issue"},
+		BreakingLabels:      []string{"breaking-change"},
+		DeprecationLabels:   []string{"deprecation"},
+		Terminology:         defaultTerminology,
+		ProfanityWords:      defaultProfanityWords,
+		InternalURLPatterns: defaultInternalURLPatterns,
+		CategoryRules:       defaultCategoryRules,
+		TicketKeys:          defaultTicketKeys,
+		Repositories:        defaultRepositories,
+		SKUs:                defaultSKUs,
+		Delivery:            defaultDelivery,
+		Retry:               github.DefaultRetryPolicy,
+	}
+}
+
+// loadConfig reads the configuration file at path, falling back to the
+// defaults for the settings it doesn't define. An empty path loads the
+// default configuration file if it exists.
+func loadConfig(path string) (Config, error) {
+	cfg := defaultConfig()
+
+	explicit := path != ""
+	if !explicit {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return cfg, nil
+		}
+		path = filepath.Join(home, defaultConfigFile)
+	} else if strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, path[2:])
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) && !explicit {
+		return cfg, nil
+	} else if err != nil {
+		return cfg, err
+	}
+
+	var fileCfg Config
+	if err := yaml.Unmarshal(data, &fileCfg); err != nil {
+		return cfg, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+
+	if len(fileCfg.DeveloperSections) > 0 {
+		if err := compileSections(fileCfg.DeveloperSections); err != nil {
+			return cfg, fmt.Errorf("invalid developer sections in %s: %w", path, err)
+		}
+		cfg.DeveloperSections = fileCfg.DeveloperSections
+	}
+	if len(fileCfg.PerformanceLabels) > 0 {
+		cfg.PerformanceLabels = fileCfg.PerformanceLabels
+	}
+	if len(fileCfg.AccessibilityLabels) > 0 {
+		cfg.AccessibilityLabels = fileCfg.AccessibilityLabels
+	}
+	if len(fileCfg.BugLabels) > 0 {
+		cfg.BugLabels = fileCfg.BugLabels
+	}
+	if len(fileCfg.KnownIssueLabels) > 0 {
+		cfg.KnownIssueLabels = fileCfg.KnownIssueLabels
+	}
+	if len(fileCfg.BreakingLabels) > 0 {
+		cfg.BreakingLabels = fileCfg.BreakingLabels
+	}
+	if len(fileCfg.DeprecationLabels) > 0 {
+		cfg.DeprecationLabels = fileCfg.DeprecationLabels
+	}
+	if len(fileCfg.ProfanityWords) > 0 {
+		cfg.ProfanityWords = fileCfg.ProfanityWords
+	}
+	if len(fileCfg.InternalURLPatterns) > 0 {
+		cfg.InternalURLPatterns = fileCfg.InternalURLPatterns
+	}
+	if len(fileCfg.TicketKeys) > 0 {
+		cfg.TicketKeys = fileCfg.TicketKeys
+	}
+	if len(fileCfg.Repositories) > 0 {
+		if err := validateRepositories(fileCfg.Repositories); err != nil {
+			return cfg, fmt.Errorf("invalid config file %s: %w", path, err)
+		}
+		cfg.Repositories = fileCfg.Repositories
+	}
+	if len(fileCfg.Terminology) > 0 {
+		if err := compileTerminology(fileCfg.Terminology); err != nil {
+			return cfg, fmt.Errorf("invalid terminology in %s: %w", path, err)
+		}
+		cfg.Terminology = fileCfg.Terminology
+	}
+	if len(fileCfg.CategoryRules) > 0 {
+		if err := compileCategoryRules(fileCfg.CategoryRules); err != nil {
+			return cfg, fmt.Errorf("invalid category rules in %s: %w", path, err)
+		}
+		cfg.CategoryRules = fileCfg.CategoryRules
+	}
+	if fileCfg.Retry.Attempts > 0 {
+		cfg.Retry.Attempts = fileCfg.Retry.Attempts
+	}
+	if fileCfg.Retry.Backoff > 0 {
+		cfg.Retry.Backoff = fileCfg.Retry.Backoff
+	}
+	if fileCfg.Retry.MaxBackoff > 0 {
+		cfg.Retry.MaxBackoff = fileCfg.Retry.MaxBackoff
+	}
+	if fileCfg.Retry.Jitter > 0 {
+		cfg.Retry.Jitter = fileCfg.Retry.Jitter
+	}
+	if len(fileCfg.SKUs) > 0 {
+		if err := validateSKUs(fileCfg.SKUs); err != nil {
+			return cfg, fmt.Errorf("invalid config file %s: %w", path, err)
+		}
+		cfg.SKUs = fileCfg.SKUs
+	}
+	if len(fileCfg.Delivery.CloudLabels) > 0 {
+		cfg.Delivery.CloudLabels = fileCfg.Delivery.CloudLabels
+	}
+	if len(fileCfg.Delivery.SelfHostedLabels) > 0 {
+		cfg.Delivery.SelfHostedLabels = fileCfg.Delivery.SelfHostedLabels
+	}
+	if fileCfg.Delivery.CloudTitle != "" {
+		cfg.Delivery.CloudTitle = fileCfg.Delivery.CloudTitle
+	}
+	if fileCfg.Delivery.SelfHostedTitle != "" {
+		cfg.Delivery.SelfHostedTitle = fileCfg.Delivery.SelfHostedTitle
+	}
+	if err := validateDelivery(cfg.Delivery); err != nil {
+		return cfg, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	if err := validateRepoGroups(fileCfg.RepoGroups, cfg.Repositories); err != nil {
+		return cfg, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	cfg.RepoGroups = fileCfg.RepoGroups
+	if err := validateMilestoneRules(fileCfg.MilestoneRules); err != nil {
+		return cfg, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	cfg.MilestoneRules = fileCfg.MilestoneRules
+	cfg.MilestoneNames = fileCfg.MilestoneNames
+	cfg.UserAgent = fileCfg.UserAgent
+	cfg.HTTPHeaders = fileCfg.HTTPHeaders
+
+	cfg.NoteTemplates = fileCfg.NoteTemplates
+	if err := compileNoteTemplates(cfg.NoteTemplates); err != nil {
+		return cfg, fmt.Errorf("invalid note template in %s: %w", path, err)
+	}
+
+	cfg.ChatLinkShortener = fileCfg.ChatLinkShortener
+	if err := cfg.ChatLinkShortener.compile(); err != nil {
+		return cfg, fmt.Errorf("invalid chat link shortener in %s: %w", path, err)
+	}
+
+	return cfg, nil
+}
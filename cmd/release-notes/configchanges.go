@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// configSchemaFiles are the files defining the server configuration; PRs
+// changing them are expected to add or change settings
+var configSchemaFiles = []string{
+	"server/public/model/config.go",
+	"server/config/client.go",
+}
+
+// SettingChange is a new or changed configuration setting
+type SettingChange struct {
+	Path        string
+	Default     string
+	Description string
+	PR          PullRequest
+}
+
+// configChangeRegexp matches a ```config-change block in a PR description
+var configChangeRegexp = regexp.MustCompile("(?s)```\\s*config-change\\s*\n(.*?)\n\\s*```")
+
+// extractSettingChanges returns the settings described in the config-change
+// block of a PR. Each line of the block describes one setting as
+// "Setting.Path | default | description".
+func extractSettingChanges(pr PullRequest) []SettingChange {
+	var changes []SettingChange
+	for _, block := range configChangeRegexp.FindAllStringSubmatch(pr.Body, -1) {
+		for _, line := range strings.Split(block[1], "\n") {
+			line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "-"))
+			if line == "" {
+				continue
+			}
+
+			fields := strings.SplitN(line, "|", 3)
+			change := SettingChange{Path: strings.TrimSpace(fields[0]), PR: pr}
+			if len(fields) > 1 {
+				change.Default = strings.TrimSpace(fields[1])
+			}
+			if len(fields) > 2 {
+				change.Description = strings.TrimSpace(fields[2])
+			}
+			changes = append(changes, change)
+		}
+	}
+
+	return changes
+}
+
+// collectSettingChanges returns the settings changed by the PRs. PRs editing
+// the config schema without a config-change block are reported with
+// placeholders so they can be documented by hand. PRs must have their files
+// fetched beforehand.
+func collectSettingChanges(prs []PullRequest) []SettingChange {
+	var changes []SettingChange
+	for _, pr := range prs {
+		if prChanges := extractSettingChanges(pr); len(prChanges) > 0 {
+			changes = append(changes, prChanges...)
+			continue
+		}
+
+		for _, file := range pr.Files {
+			if pr.RepoURL == mattermostRepoURL && containsString(configSchemaFiles, file) {
+				changes = append(changes, SettingChange{
+					Path:        "?",
+					Default:     "?",
+					Description: "Config schema changed, see the PR: " + pr.Title,
+					PR:          pr,
+				})
+				break
+			}
+		}
+	}
+
+	return changes
+}
+
+// printSettingChanges prints the "New/changed settings" table for admins
+func printSettingChanges(changes []SettingChange) {
+	if len(changes) == 0 {
+		return
+	}
+
+	fmt.Println("Administration: New/changed settings")
+	fmt.Println()
+	fmt.Println("| Setting | Default | Description | PR |")
+	fmt.Println("| --- | --- | --- | --- |")
+	for _, change := range changes {
+		fmt.Printf("| %s | %s | %s | %s#%d |\n",
+			escapeTableCell(change.Path), escapeTableCell(change.Default), escapeTableCell(change.Description),
+			repoNameFromURL(change.PR.RepoURL), change.PR.Number)
+	}
+	fmt.Println()
+}
+
+// escapeTableCell makes text safe to use in a Markdown table cell
+func escapeTableCell(text string) string {
+	return strings.ReplaceAll(strings.Join(strings.Fields(text), " "), "|", "\\|")
+}
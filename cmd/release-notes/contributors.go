@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Contributor is the author of PRs of the release
+type Contributor struct {
+	Login string
+	// FirstTime is set for authors with no merged PR in the repositories
+	// before the ones of the release, with --first-time-contributors
+	FirstTime bool
+	// firstPRs is when the first PR of the release was opened in each
+	// repository the author contributed to
+	firstPRs map[string]time.Time
+}
+
+// isBot reports whether a login is a GitHub App, e.g. dependabot[bot]
+func isBot(login string) bool {
+	return strings.HasSuffix(login, "[bot]")
+}
+
+// collectContributors returns the authors of the PRs, without bots, sorted by
+// login
+func collectContributors(prs []PullRequest) []Contributor {
+	byLogin := make(map[string]*Contributor)
+	for _, pr := range prs {
+		login := pr.User.Login
+		if pr.Manual || login == "" || isBot(login) {
+			continue
+		}
+
+		contributor := byLogin[strings.ToLower(login)]
+		if contributor == nil {
+			contributor = &Contributor{Login: login, firstPRs: make(map[string]time.Time)}
+			byLogin[strings.ToLower(login)] = contributor
+		}
+		if first, ok := contributor.firstPRs[pr.RepoURL]; !ok || pr.CreatedAt.Before(first) {
+			contributor.firstPRs[pr.RepoURL] = pr.CreatedAt
+		}
+	}
+
+	contributors := make([]Contributor, 0, len(byLogin))
+	for _, contributor := range byLogin {
+		contributors = append(contributors, *contributor)
+	}
+	sort.Slice(contributors, func(i, j int) bool {
+		return strings.ToLower(contributors[i].Login) < strings.ToLower(contributors[j].Login)
+	})
+	return contributors
+}
+
+// hasEarlierMergedPR reports whether the author has a merged PR in the
+// repository opened before the given time, using the search API
+func hasEarlierMergedPR(repoURL string, login string, before time.Time) (bool, error) {
+	query := fmt.Sprintf("repo:%s is:pr is:merged author:%s created:<%s", repoNameFromURL(repoURL), login, before.UTC().Format(time.RFC3339))
+
+	var result struct {
+		TotalCount int `json:"total_count"`
+	}
+	if err := apiClient.GetJSON(githubAPIURL+"/search/issues?per_page=1&q="+url.QueryEscape(query), &result); err != nil {
+		return false, err
+	}
+	return result.TotalCount > 0, nil
+}
+
+// markFirstTimeContributors sets FirstTime for the contributors whose PRs in
+// the release are their first merged ones in the repositories. Only GitHub
+// repositories are searched.
+func markFirstTimeContributors(contributors []Contributor) error {
+	for i := range contributors {
+		firstTime := true
+		for repoURL, first := range contributors[i].firstPRs {
+			if !isGitHubRepo(repoURL) || first.IsZero() {
+				firstTime = false
+				break
+			}
+
+			earlier, err := hasEarlierMergedPR(repoURL, contributors[i].Login, first)
+			if err != nil {
+				return fmt.Errorf("error searching the PRs of %s: %w", contributors[i].Login, err)
+			}
+			if earlier {
+				firstTime = false
+				break
+			}
+		}
+		contributors[i].FirstTime = firstTime
+	}
+	return nil
+}
+
+// printContributors prints the "Thanks to our contributors" section of the
+// changelog, with the first-time contributors highlighted. The HTML page
+// lists them itself.
+func printContributors(contributors []Contributor) {
+	if len(contributors) == 0 || outputFormat == formatHTML {
+		return
+	}
+
+	var names []string
+	for _, contributor := range contributors {
+		name := "@" + contributor.Login
+		if outputFormat == formatMarkdown {
+			name = fmt.Sprintf("[@%s](https://github.com/%s)", contributor.Login, contributor.Login)
+		}
+		if contributor.FirstTime {
+			name += " (first contribution)"
+			if outputFormat == formatMarkdown {
+				name = "**" + name + "**"
+			}
+		}
+		names = append(names, name)
+	}
+
+	if outputFormat == formatMarkdown {
+		fmt.Printf("### Thanks to our contributors\n\n")
+	} else {
+		fmt.Printf("Thanks to our contributors:\n\n")
+	}
+	fmt.Println(strings.Join(names, ", "))
+	fmt.Println()
+}
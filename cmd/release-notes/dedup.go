@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/jespino/github-mm-release-notes/extract"
+)
+
+// Modes of --dedup
+const (
+	// dedupExact merges the notes that only differ in case, punctuation
+	// and spacing
+	dedupExact = "exact"
+	// dedupFuzzy also merges the notes at least --dedup-similarity alike
+	dedupFuzzy = "fuzzy"
+)
+
+var dedupModes = []string{dedupExact, dedupFuzzy}
+
+// normalizeNote returns a release note lowercased, without punctuation and
+// with single spaces, for comparing notes
+func normalizeNote(note string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(note) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		case unicode.IsSpace(r) || unicode.IsPunct(r) || unicode.IsSymbol(r):
+			b.WriteRune(' ')
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// noteSimilarity returns how alike two normalized notes are, from 0 to 1,
+// from the edit distance between them
+func noteSimilarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longest := max(len(ra), len(rb))
+	if longest == 0 {
+		return 1
+	}
+
+	// Levenshtein distance keeping a single row of the matrix
+	row := make([]int, len(rb)+1)
+	for j := range row {
+		row[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		diagonal := row[0]
+		row[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			above := row[j]
+			row[j] = min(row[j]+1, row[j-1]+1, diagonal+cost)
+			diagonal = above
+		}
+	}
+
+	return 1 - float64(row[len(rb)])/float64(longest)
+}
+
+// dedupNotes merges the PRs with the same release note, e.g. a feature
+// landing in mattermost and enterprise, into the entry of the first one,
+// listing the rest as its duplicates so the entry links to all of them. With
+// the fuzzy mode, notes at least similarity alike are merged too.
+func dedupNotes(prs []PullRequest, mode string, similarity float64) []PullRequest {
+	var result []PullRequest
+	var normalized []string
+	for _, pr := range prs {
+		note := prReleaseNote(pr)
+		if pr.Manual || !extract.HasReleaseNote(note) {
+			result = append(result, pr)
+			normalized = append(normalized, "")
+			continue
+		}
+
+		key := normalizeNote(note)
+		merged := false
+		for i, other := range normalized {
+			if other == "" {
+				continue
+			}
+			if other == key || (mode == dedupFuzzy && noteSimilarity(other, key) >= similarity) {
+				result[i].Duplicates = append(result[i].Duplicates, pr)
+				merged = true
+				break
+			}
+		}
+		if merged {
+			continue
+		}
+
+		result = append(result, pr)
+		normalized = append(normalized, key)
+	}
+
+	if merged := len(prs) - len(result); merged > 0 {
+		logger.Info("Merged duplicate release notes", "merged", merged, "mode", mode)
+	}
+	return result
+}
+
+// applyDedup merges the duplicate release notes when requested with --dedup
+func applyDedup(prs []PullRequest) ([]PullRequest, error) {
+	switch dedupMode {
+	case "":
+		return prs, nil
+	case dedupExact, dedupFuzzy:
+		if dedupSimilarity <= 0 || dedupSimilarity > 1 {
+			return nil, fmt.Errorf("invalid --dedup-similarity %v, must be between 0 and 1", dedupSimilarity)
+		}
+		return dedupNotes(prs, dedupMode, dedupSimilarity), nil
+	default:
+		return nil, fmt.Errorf("invalid --dedup mode %q, must be one of: %s", dedupMode, strings.Join(dedupModes, ", "))
+	}
+}
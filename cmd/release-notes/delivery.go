@@ -0,0 +1,147 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// splitDelivery renders the notes twice, for the Cloud customers, who get
+// the changes continuously and know them by date, and for the self-hosted
+// ones, who upgrade to a version
+var splitDelivery bool
+
+// Delivery configures the Cloud and self-hosted variants of the notes
+// rendered with --split-delivery
+type Delivery struct {
+	// CloudLabels mark the changes only shipped to Cloud
+	CloudLabels []string `yaml:"cloud_labels"`
+	// SelfHostedLabels mark the changes only shipped to self-hosted
+	// installations
+	SelfHostedLabels []string `yaml:"self_hosted_labels"`
+	// CloudTitle and SelfHostedTitle are the templates of the titles of the
+	// variants, with the {{.Version}}, {{.Milestone}} and {{.Date}}
+	// placeholders
+	CloudTitle      string `yaml:"cloud_title"`
+	SelfHostedTitle string `yaml:"self_hosted_title"`
+}
+
+// defaultDelivery is used for the settings the configuration doesn't define
+var defaultDelivery = Delivery{
+	CloudLabels:      []string{"cloud-only"},
+	SelfHostedLabels: []string{"self-hosted-only"},
+	CloudTitle:       "Mattermost Cloud ({{.Date}})",
+	SelfHostedTitle:  "{{.Version}}",
+}
+
+// DeliveryTitleData holds the placeholders available in the title templates
+type DeliveryTitleData struct {
+	// Version is the public name of the milestone
+	Version   string
+	Milestone string
+	// Date is the release date of the milestone names of the configuration,
+	// or today
+	Date string
+}
+
+// deliveryVariant is a variant of the notes rendered with --split-delivery
+type deliveryVariant struct {
+	// Name is added to the output file name, e.g. notes-cloud.md
+	Name  string
+	Title string
+	// Excluded labels mark the changes not shipped to the variant
+	Excluded []string
+}
+
+// deliveryVariants returns the self-hosted and Cloud variants of the notes of
+// a milestone
+func deliveryVariants(milestoneTitle string) ([]deliveryVariant, error) {
+	data := DeliveryTitleData{
+		Version:   milestoneDisplayName(milestoneTitle),
+		Milestone: milestoneTitle,
+		Date:      time.Now().Format("January 2, 2006"),
+	}
+	if name, ok := findMilestoneName(milestoneTitle); ok && name.Date != "" {
+		data.Date = name.Date
+	}
+
+	selfHostedTitle, err := executeDeliveryTitle("self_hosted_title", config.Delivery.SelfHostedTitle, data)
+	if err != nil {
+		return nil, err
+	}
+	cloudTitle, err := executeDeliveryTitle("cloud_title", config.Delivery.CloudTitle, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return []deliveryVariant{
+		{Name: "self-hosted", Title: selfHostedTitle, Excluded: config.Delivery.CloudLabels},
+		{Name: "cloud", Title: cloudTitle, Excluded: config.Delivery.SelfHostedLabels},
+	}, nil
+}
+
+// executeDeliveryTitle renders the title template of a variant
+func executeDeliveryTitle(name string, text string, data DeliveryTitleData) (string, error) {
+	tmpl, err := template.New(name).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s: %w", name, err)
+	}
+	var buf strings.Builder
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("invalid %s: %w", name, err)
+	}
+	return buf.String(), nil
+}
+
+// deliveryOutputFile returns the file of a variant, named after the --output
+// file, e.g. notes-cloud.md for notes.md
+func deliveryOutputFile(path string, variant string) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "-" + variant + ext
+}
+
+// printDeliveryVariants prints the self-hosted and Cloud variants of the
+// notes of a milestone, each leaving out the changes only shipped to the
+// other, to their own file when --output is given
+func printDeliveryVariants(prs []PullRequest, milestoneTitle string, changeLogType string) error {
+	variants, err := deliveryVariants(milestoneTitle)
+	if err != nil {
+		return err
+	}
+
+	for i, variant := range variants {
+		var kept []PullRequest
+		for _, pr := range prs {
+			if !hasAnyLabel(pr, variant.Excluded) {
+				kept = append(kept, pr)
+			}
+		}
+
+		restore := func() {}
+		if outputFile != "" {
+			if restore, err = redirectOutput(deliveryOutputFile(outputFile, variant.Name)); err != nil {
+				return err
+			}
+		} else if i > 0 {
+			fmt.Println()
+		}
+		err := printReleaseNotesByArea(kept, variant.Title, changeLogType)
+		restore()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateDelivery checks the title templates of the variants
+func validateDelivery(delivery Delivery) error {
+	for name, text := range map[string]string{"cloud_title": delivery.CloudTitle, "self_hosted_title": delivery.SelfHostedTitle} {
+		if _, err := executeDeliveryTitle(name, text, DeliveryTitleData{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// bugReportURL is where users are asked to report crashes
+const bugReportURL = "https://github.com/jespino/github-mm-release-notes/issues"
+
+// originalArgs are the command line arguments before main strips the command
+var originalArgs = append([]string(nil), os.Args[1:]...)
+
+// recoverWithDiagnostics must be deferred at the start of main. On a panic it
+// writes a diagnostics bundle to a temporary file and asks the user to attach
+// it to a bug report instead of dumping the stack trace.
+func recoverWithDiagnostics() {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	stack := debug.Stack()
+	path, err := writeDiagnosticsBundle(r, stack)
+	if err != nil {
+		// Fall back to printing what would have been in the bundle
+		fmt.Fprintf(os.Stderr, "Unexpected error: %v\n\n%s\n", r, stack)
+		os.Exit(2)
+	}
+
+	fmt.Fprintf(os.Stderr, "Unexpected error: %v\n\n", r)
+	fmt.Fprintf(os.Stderr, "This is a bug. A diagnostics bundle was written to:\n\n  %s\n\n", path)
+	fmt.Fprintf(os.Stderr, "Please review it and attach it to a bug report at %s\n", bugReportURL)
+	os.Exit(2)
+}
+
+// recoverGoroutine must be deferred at the start of the goroutines fetching
+// from the APIs, whose panics the recoverWithDiagnostics of main can't
+// catch. It turns a panic into an error pointing to a diagnostics bundle, so
+// the panic fails the run like recoverWithDiagnostics does.
+func recoverGoroutine(err *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	stack := debug.Stack()
+	path, bundleErr := writeDiagnosticsBundle(r, stack)
+	if bundleErr != nil {
+		*err = fmt.Errorf("unexpected error: %v\n\n%s", r, stack)
+		return
+	}
+	*err = fmt.Errorf("unexpected error: %v. This is a bug, a diagnostics bundle was written to %s, please review it and attach it to a bug report at %s", r, path, bugReportURL)
+}
+
+// writeDiagnosticsBundle writes the panic, its stack, the environment, the
+// configuration and the recent API calls to a temporary file, leaving out
+// tokens and header values, and returns its path
+func writeDiagnosticsBundle(panicValue any, stack []byte) (string, error) {
+	file, err := os.CreateTemp("", "release-notes-extractor-diagnostics-*.txt")
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Panic: %v\n", panicValue)
+	fmt.Fprintf(&b, "Time: %s\n", time.Now().UTC().Format(time.RFC3339))
+	fmt.Fprintf(&b, "Version: %s\n", userAgent())
+	fmt.Fprintf(&b, "Go: %s %s/%s\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	fmt.Fprintf(&b, "Arguments: %s\n", strings.Join(redactArgs(originalArgs), " "))
+	fmt.Fprintf(&b, "API URL: %s\n", apiBaseURL)
+
+	b.WriteString("\nConfiguration:\n\n")
+	safeConfig := config
+	safeConfig.HTTPHeaders = make(map[string]string, len(config.HTTPHeaders))
+	for name := range config.HTTPHeaders {
+		safeConfig.HTTPHeaders[name] = "REDACTED"
+	}
+	configYAML, err := yaml.Marshal(safeConfig)
+	if err != nil {
+		fmt.Fprintf(&b, "error marshaling the configuration: %v\n", err)
+	}
+	b.Write(configYAML)
+
+	b.WriteString("\nRecent API calls:\n\n")
+	for _, call := range apiClient.RecentCalls() {
+		status := fmt.Sprint(call.Status)
+		if call.Err != "" {
+			status = "failed: " + call.Err
+		}
+		fmt.Fprintf(&b, "%s %s %s %s (%s)\n", call.Time.UTC().Format(time.RFC3339), call.Method, call.URL, status, call.Duration.Round(time.Millisecond))
+	}
+
+	fmt.Fprintf(&b, "\nStack:\n\n%s", stack)
+
+	if _, err := file.WriteString(b.String()); err != nil {
+		return "", err
+	}
+
+	return file.Name(), nil
+}
+
+// secretFlags are the flags whose values must not end up in a bug report
+var secretFlags = []string{"token", "gitlab-token", "gitea-token", "claudetoken", "mattermost-token", "mattermost-webhook-url", "notify-url"}
+
+// redactArgs returns the command line arguments with the values of the
+// secret flags replaced
+func redactArgs(args []string) []string {
+	result := make([]string, len(args))
+	redactNext := false
+	for i, arg := range args {
+		if redactNext {
+			result[i] = "REDACTED"
+			redactNext = false
+			continue
+		}
+
+		result[i] = arg
+		name, _, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !strings.HasPrefix(arg, "-") || !containsString(secretFlags, name) {
+			continue
+		}
+		if hasValue {
+			result[i] = arg[:strings.Index(arg, "=")+1] + "REDACTED"
+		} else {
+			redactNext = true
+		}
+	}
+	return result
+}
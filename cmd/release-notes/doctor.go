@@ -0,0 +1,169 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jespino/github-mm-release-notes/github"
+)
+
+// Fine-grained personal access token permissions used by the tool
+const (
+	permMetadata     = "Metadata (read)"
+	permIssues       = "Issues (read)"
+	permPullRequests = "Pull requests (read)"
+	permContents     = "Contents (read)"
+	permContentsRW   = "Contents (read and write)"
+)
+
+// Operation is something the tool does with the GitHub API, along with the
+// fine-grained token permissions it needs on the repositories
+type Operation struct {
+	Name        string
+	Permissions []string
+	Enabled     bool
+}
+
+// operations returns the operations of the tool, enabled according to the
+// command and flags given
+func operations(command string) []Operation {
+	return []Operation{
+		{
+			Name:        "List milestones and the PRs with release notes",
+			Permissions: []string{permMetadata, permIssues, permPullRequests},
+			Enabled:     command == "",
+		},
+		{
+			Name:        "Fetch changed files (--areas, --paths, --checklist, --settings, --developer-sections)",
+			Permissions: []string{permPullRequests},
+			Enabled:     useAreas || includePaths.exprs != nil || excludePaths.exprs != nil || checklistFile != "" || showSettings || showDevSections,
+		},
+		{
+			Name:        "Fetch diff stats and merge times (--impact, --sort-by-size, --rc)",
+			Permissions: []string{permPullRequests},
+			Enabled:     showImpact || sortBySize || rcNumber > 0,
+		},
+		{
+			Name:        "Read issue and PR timelines (--carryover, --note-history)",
+			Permissions: []string{permIssues, permPullRequests},
+			Enabled:     showCarryover || showNoteHistory,
+		},
+		{
+			Name:        "List open issues (--known-issues)",
+			Permissions: []string{permIssues},
+			Enabled:     showKnownIssues,
+		},
+		{
+			Name:        "Read release candidate tags (--rc)",
+			Permissions: []string{permContents},
+			Enabled:     rcNumber > 0,
+		},
+		{
+			Name:        "Create and update GitHub Releases and their assets (publish)",
+			Permissions: []string{permContentsRW},
+			Enabled:     command == "publish",
+		},
+	}
+}
+
+// tokenType describes the kind of token from its prefix
+func tokenType(token string) string {
+	switch {
+	case token == "":
+		return "none (unauthenticated)"
+	case strings.HasPrefix(token, "github_pat_"):
+		return "fine-grained personal access token"
+	case strings.HasPrefix(token, "ghp_"):
+		return "classic personal access token"
+	case strings.HasPrefix(token, "gho_"):
+		return "OAuth token"
+	case strings.HasPrefix(token, "ghs_"), strings.HasPrefix(token, "ghu_"):
+		return "GitHub App token"
+	default:
+		return "unknown"
+	}
+}
+
+// probePermission checks whether the token has a permission on a repository
+// with a harmless read request. Write permissions can't be checked without
+// writing, so they are inferred from the push permission of the repository.
+func probePermission(repoURL string, permission string) error {
+	switch permission {
+	case permMetadata:
+		return apiClient.GetJSON(repoURL, &struct{}{})
+	case permIssues:
+		return apiClient.GetJSON(repoURL+"/issues?per_page=1", &[]struct{}{})
+	case permPullRequests:
+		return apiClient.GetJSON(repoURL+"/pulls?per_page=1", &[]struct{}{})
+	case permContents:
+		return apiClient.GetJSON(repoURL+"/commits?per_page=1", &[]struct{}{})
+	case permContentsRW:
+		var repo struct {
+			Permissions struct {
+				Push bool `json:"push"`
+			} `json:"permissions"`
+		}
+		if err := apiClient.GetJSON(repoURL, &repo); err != nil {
+			return err
+		}
+		if !repo.Permissions.Push {
+			return errors.New("the token can't write to this repository")
+		}
+	}
+	return nil
+}
+
+// runDoctor implements the doctor command, which reports the permissions
+// needed by the operations enabled by the given flags and checks the token
+// has them on every repository
+func runDoctor() error {
+	fmt.Printf("Token: %s\n\n", tokenType(authToken))
+
+	// doctor itself is not an operation, check what a regular run would need
+	ops := operations("")
+	if doctorCommand != "" {
+		ops = operations(doctorCommand)
+	}
+
+	var needed []string
+	fmt.Println("Enabled operations and the fine-grained token permissions they need:")
+	for _, op := range ops {
+		if !op.Enabled {
+			continue
+		}
+		fmt.Printf("  %s: %s\n", op.Name, strings.Join(op.Permissions, ", "))
+		for _, permission := range op.Permissions {
+			if !containsString(needed, permission) {
+				needed = append(needed, permission)
+			}
+		}
+	}
+	fmt.Println()
+
+	problems := 0
+	fmt.Println("Repository permissions:")
+	for _, repoURL := range allRepoURLs {
+		fmt.Printf("  %s:\n", repoNameFromURL(repoURL))
+		for _, permission := range needed {
+			status := "ok"
+			if err := probePermission(repoURL, permission); err != nil {
+				problems++
+				status = "missing"
+				var apiErr *github.APIError
+				if errors.As(err, &apiErr) {
+					status += fmt.Sprintf(" (%d: %s)", apiErr.StatusCode, apiErr.Message())
+				} else {
+					status += " (" + err.Error() + ")"
+				}
+			}
+			fmt.Printf("    %s: %s\n", permission, status)
+		}
+	}
+
+	if problems > 0 {
+		return fmt.Errorf("%d permission checks failed", problems)
+	}
+
+	return nil
+}
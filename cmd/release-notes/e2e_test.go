@@ -0,0 +1,103 @@
+package main
+
+import (
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jespino/github-mm-release-notes/fakegithub"
+)
+
+// runMainEnv makes the test binary run the tool instead of the tests, so the
+// end-to-end tests run it like a user would
+const runMainEnv = "RELEASE_NOTES_RUN_MAIN"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(runMainEnv) == "1" {
+		main()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// runTool runs the tool against the fixtures served by fakegithub, returning
+// its standard output and whether it succeeded
+func runTool(t *testing.T, fixtures *fakegithub.Fixtures, args ...string) (string, bool) {
+	t.Helper()
+
+	server := httptest.NewServer(fakegithub.NewHandler(fixtures))
+	defer server.Close()
+
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(configPath, []byte("repositories:\n  - name: o/r\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	args = append([]string{"--token=test", "--api-url=" + server.URL, "--config=" + configPath, "--no-cache", "--repo=o/r"}, args...)
+	cmd := exec.Command(os.Args[0], args...)
+	cmd.Env = append(os.Environ(), runMainEnv+"=1", "HOME="+dir)
+	var stdout strings.Builder
+	cmd.Stdout = &stdout
+	err := cmd.Run()
+	if _, ok := err.(*exec.ExitError); err != nil && !ok {
+		t.Fatal(err)
+	}
+	return stdout.String(), err == nil
+}
+
+func e2eFixtures() *fakegithub.Fixtures {
+	merged := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	return &fakegithub.Fixtures{Repos: map[string]*fakegithub.Repo{
+		"o/r": {
+			Milestones: []fakegithub.Milestone{{Number: 1, Title: "v1.0.0"}},
+			PullRequests: []fakegithub.PullRequest{
+				{
+					Issue:    fakegithub.Issue{Number: 1, Title: "Add the foo setting", Author: "alice", Body: "Server:\n```release-note\nAdded the foo setting.\n```\nWebapp:\n```release-note\nAdded the foo toggle.\n```\n", State: "closed", Labels: []string{"release-note"}, Milestone: 1},
+					MergedAt: &merged,
+				},
+				{
+					Issue:    fakegithub.Issue{Number: 2, Title: "Fix the bar crash", Author: "bob", Body: "```release-note\nFixed a crash when opening bar.\n```\n", State: "closed", Labels: []string{"release-note", "bug"}, Milestone: 1},
+					MergedAt: &merged,
+				},
+				{
+					Issue:    fakegithub.Issue{Number: 3, Title: "Refactor internals", Author: "carol", Body: "```release-note\nNONE\n```\n", State: "closed", Labels: []string{"release-note"}, Milestone: 1},
+					MergedAt: &merged,
+				},
+			},
+		},
+	}}
+}
+
+func TestEndToEndMarkdown(t *testing.T) {
+	out, ok := runTool(t, e2eFixtures(), "--milestone=v1.0.0", "--format=markdown")
+	if !ok {
+		t.Fatalf("the tool failed, output:\n%s", out)
+	}
+
+	for _, want := range []string{
+		"### New Features",
+		// The notes of several blocks stay apart in the bullet
+		"- Added the foo setting.  \n  Added the foo toggle. ([#1](https://github.com/o/r/pull/1))",
+		"### Bug Fixes",
+		"- Fixed a crash when opening bar. ([#2](https://github.com/o/r/pull/2))",
+		"o/r#3: Refactor internals",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output doesn't contain %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "- NONE") {
+		t.Errorf("the NONE note is listed as a note:\n%s", out)
+	}
+}
+
+func TestEndToEndUnknownMilestone(t *testing.T) {
+	if out, ok := runTool(t, e2eFixtures(), "--milestone=v9.9.9"); ok {
+		t.Fatalf("the tool succeeded with an unknown milestone, output:\n%s", out)
+	}
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"fmt"
+	"os/user"
+	"time"
+)
+
+// exclusionReason is the reason recorded by the exclude command
+var exclusionReason string
+
+// Exclusion records why an entry of a release set was left out of the notes.
+// The entry stays in the file, so the decision can be reviewed later in the
+// release cycle and reverted with the restore command.
+type Exclusion struct {
+	Reason string    `yaml:"reason"`
+	At     time.Time `yaml:"at"`
+	// By is the user who excluded the entry
+	By string `yaml:"by,omitempty"`
+}
+
+// migrateExclusions upgrades a release set to the version with excluded
+// entries, which older versions of the tool would render. There is nothing
+// to change in the files.
+func migrateExclusions(doc map[string]any) error {
+	return nil
+}
+
+// currentUser returns the name of the user running the tool, recorded in the
+// exclusions
+func currentUser() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return ""
+}
+
+// findReleaseEntries returns the indexes of the entries of the release set
+// for the PRs given with --pr, failing if any is not in the set
+func findReleaseEntries(set ReleaseSet, refs []string) ([]int, error) {
+	var indexes []int
+	for _, ref := range refs {
+		repo, number, err := parsePRRef(ref)
+		if err != nil {
+			return nil, err
+		}
+
+		found := false
+		for i, entry := range set.Entries {
+			if entry.Repo == repo && entry.Number == number {
+				indexes = append(indexes, i)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("%s is not in the release set", ref)
+		}
+	}
+	return indexes, nil
+}
+
+// runExclude implements the exclude command, which leaves the entries of the
+// PRs given with --pr out of the notes rendered from a release set,
+// recording the --reason, when and by whom
+func runExclude() error {
+	if releaseSetFile == "" {
+		return fmt.Errorf("the --release-set flag with the release set file is required")
+	}
+	if len(prRefs) == 0 {
+		return fmt.Errorf("the --pr flag with the PRs to exclude is required")
+	}
+	if exclusionReason == "" {
+		return fmt.Errorf("the --reason flag explaining the exclusion is required")
+	}
+
+	set, _, err := loadReleaseSet(releaseSetFile)
+	if err != nil {
+		return err
+	}
+	indexes, err := findReleaseEntries(set, prRefs)
+	if err != nil {
+		return err
+	}
+
+	exclusion := Exclusion{Reason: exclusionReason, At: time.Now().UTC(), By: currentUser()}
+	for _, i := range indexes {
+		entry := &set.Entries[i]
+		if entry.Excluded != nil {
+			fmt.Printf("Updated the exclusion of %s#%d, was: %s\n", entry.Repo, entry.Number, entry.Excluded.Reason)
+		} else {
+			fmt.Printf("Excluded %s#%d: %s\n", entry.Repo, entry.Number, entry.Title)
+		}
+		entry.Excluded = &exclusion
+	}
+
+	return writeReleaseSet(releaseSetFile, set)
+}
+
+// runRestore implements the restore command, which brings back the entries
+// of the PRs given with --pr excluded from a release set
+func runRestore() error {
+	if releaseSetFile == "" {
+		return fmt.Errorf("the --release-set flag with the release set file is required")
+	}
+	if len(prRefs) == 0 {
+		return fmt.Errorf("the --pr flag with the PRs to restore is required")
+	}
+
+	set, _, err := loadReleaseSet(releaseSetFile)
+	if err != nil {
+		return err
+	}
+	indexes, err := findReleaseEntries(set, prRefs)
+	if err != nil {
+		return err
+	}
+
+	for _, i := range indexes {
+		entry := &set.Entries[i]
+		if entry.Excluded == nil {
+			fmt.Printf("%s#%d is not excluded\n", entry.Repo, entry.Number)
+			continue
+		}
+		fmt.Printf("Restored %s#%d, excluded on %s: %s\n", entry.Repo, entry.Number, entry.Excluded.At.Format(time.DateOnly), entry.Excluded.Reason)
+		entry.Excluded = nil
+	}
+
+	return writeReleaseSet(releaseSetFile, set)
+}
+
+// printExcludedEntries lists the entries excluded from a release set, with
+// their reasons, for the reviewers
+func printExcludedEntries(set ReleaseSet) {
+	var excluded []ReleaseEntry
+	for _, entry := range set.Entries {
+		if entry.Excluded != nil {
+			excluded = append(excluded, entry)
+		}
+	}
+	if len(excluded) == 0 {
+		return
+	}
+
+	out := reviewOutput()
+	fmt.Fprintln(out, "Excluded entries (restore them with the restore command):")
+	fmt.Fprintln(out)
+	for _, entry := range excluded {
+		fmt.Fprintf(out, "- %s#%d: %s (%s)\n", entry.Repo, entry.Number, entry.Title, entry.Excluded.Reason)
+	}
+	fmt.Fprintln(out)
+}
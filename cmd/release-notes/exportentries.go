@@ -0,0 +1,115 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/jespino/github-mm-release-notes/extract"
+)
+
+// noteURLRegexp matches the URLs in a release note, e.g. to the docs
+var noteURLRegexp = regexp.MustCompile(`https?://[^\s)\]>"']+`)
+
+// unsafeFileNameRegexp matches the characters left out of the names of the
+// exported files
+var unsafeFileNameRegexp = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// ExportedEntry is the file written for each entry by --export-entries, for
+// the docs tooling, e.g. to render "since version X" badges
+type ExportedEntry struct {
+	// Version is the public name of the milestone, e.g. "v10.5.0"
+	Version   string `json:"version"`
+	Milestone string `json:"milestone"`
+	Repo      string `json:"repo"`
+	// PR is 0 for hand-written entries
+	PR    int    `json:"pr,omitempty"`
+	Title string `json:"title"`
+	Note  string `json:"note"`
+	// Category is the type of change, guessed when Provisional
+	Category    string   `json:"category"`
+	Provisional bool     `json:"provisional,omitempty"`
+	Labels      []string `json:"labels,omitempty"`
+	// Links are the PR, the PRs with the same note merged with --dedup and the
+	// URLs found in the note
+	Links []string `json:"links,omitempty"`
+}
+
+// exportedEntry returns the exported data of a PR with a release note
+func exportedEntry(pr PullRequest, milestoneTitle string) ExportedEntry {
+	category, provisional := changeType(pr)
+	entry := ExportedEntry{
+		Version:     milestoneDisplayName(milestoneTitle),
+		Milestone:   milestoneTitle,
+		Repo:        repoNameFromURL(pr.RepoURL),
+		Title:       pr.Title,
+		Note:        releaseNoteForPR(pr),
+		Category:    category,
+		Provisional: provisional,
+	}
+	if !pr.Manual {
+		entry.PR = pr.Number
+		entry.Links = append(entry.Links, prURL(pr))
+	}
+	for _, duplicate := range pr.Duplicates {
+		entry.Links = append(entry.Links, prURL(duplicate))
+	}
+	for _, label := range pr.Labels {
+		entry.Labels = append(entry.Labels, label.Name)
+	}
+	for _, link := range noteURLRegexp.FindAllString(entry.Note, -1) {
+		link = strings.TrimRight(link, ".,;:!?")
+		if !containsString(entry.Links, link) {
+			entry.Links = append(entry.Links, link)
+		}
+	}
+	return entry
+}
+
+// exportedEntryFileName returns the name of the file of an entry, from its
+// repository and number, e.g. mattermost-mattermost-123.json, or from its
+// milestone and position for hand-written entries
+func exportedEntryFileName(entry ExportedEntry, manualIndex int) string {
+	name := fmt.Sprintf("%s-%d", entry.Repo, entry.PR)
+	if entry.PR == 0 {
+		name = fmt.Sprintf("manual-%s-%d", entry.Milestone, manualIndex)
+	}
+	return unsafeFileNameRegexp.ReplaceAllString(strings.ReplaceAll(name, "/", "-"), "_") + ".json"
+}
+
+// exportEntries writes a JSON file for each entry with a release note to the
+// directory given with --export-entries, which the docs site ingests. The
+// files of other entries already in the directory are kept, so the entries of
+// several milestones can be exported to the same directory.
+func exportEntries(dir string, prs []PullRequest, milestoneTitle string) (int, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return 0, err
+	}
+
+	written, manualCount := 0, 0
+	for _, pr := range prs {
+		if !extract.HasReleaseNote(prReleaseNote(pr)) {
+			continue
+		}
+
+		entry := exportedEntry(pr, milestoneTitle)
+		data, err := json.MarshalIndent(entry, "", "  ")
+		if err != nil {
+			return written, err
+		}
+
+		if pr.Manual {
+			manualCount++
+		}
+		path := filepath.Join(dir, exportedEntryFileName(entry, manualCount))
+		if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+			return written, err
+		}
+		written++
+	}
+
+	return written, nil
+}
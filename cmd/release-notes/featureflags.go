@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// FeatureFlag is a feature flag mentioned by a PR
+type FeatureFlag struct {
+	Name      string
+	DefaultOn bool
+	Graduated bool // The PR removes the flag, making the feature generally available
+}
+
+var (
+	// featureFlagRegexps match the ways PR descriptions refer to feature flags:
+	// the config setting, its environment variable or a "feature flag X" mention
+	featureFlagRegexps = []*regexp.Regexp{
+		regexp.MustCompile(`FeatureFlags\.(\w+)`),
+		regexp.MustCompile(`MM_FEATUREFLAGS_(\w+)`),
+		regexp.MustCompile("[Ff]eature[- ][Ff]lag\\s*:?\\s*(?:`(\\w+)`|([A-Z][A-Za-z0-9]+))"),
+	}
+	featureFlagLabelRegexp     = regexp.MustCompile(`(?i)^feature[- ]flag\s*[:/]\s*(\w+)$`)
+	featureFlagGraduatedRegexp = regexp.MustCompile(`(?i)(remov(e|es|ed|ing)|graduat(e|es|ed|ing)|clean(s|ed|ing)? up)\s+(the\s+)?feature[- ]flag`)
+	featureFlagDefaultOnRegexp = regexp.MustCompile(`(?i)(enabled|on|true) by default|defaults?\s*(to|:|=)?\s*(on|true|enabled)\b`)
+)
+
+// extractFeatureFlags returns the feature flags mentioned in the labels and
+// description of a PR
+func extractFeatureFlags(pr PullRequest) []FeatureFlag {
+	var names []string
+	addName := func(name string) {
+		// "feature flag FeatureFlags.X" names the flag X, not FeatureFlags
+		if name != "" && name != "FeatureFlags" && !containsString(names, name) {
+			names = append(names, name)
+		}
+	}
+
+	for _, label := range pr.Labels {
+		if matches := featureFlagLabelRegexp.FindStringSubmatch(label.Name); matches != nil {
+			addName(matches[1])
+		}
+	}
+	for _, re := range featureFlagRegexps {
+		for _, matches := range re.FindAllStringSubmatch(pr.Body, -1) {
+			for _, name := range matches[1:] {
+				addName(name)
+			}
+		}
+	}
+
+	graduated := featureFlagGraduatedRegexp.MatchString(pr.Body)
+	defaultOn := featureFlagDefaultOnRegexp.MatchString(pr.Body)
+
+	flags := make([]FeatureFlag, 0, len(names))
+	for _, name := range names {
+		flags = append(flags, FeatureFlag{Name: name, DefaultOn: defaultOn, Graduated: graduated})
+	}
+
+	return flags
+}
+
+// featureFlagAnnotation returns the annotation appended to the release note
+// of a PR introducing features behind flags, e.g. "(behind feature flag X,
+// default off)", or an empty string
+func featureFlagAnnotation(pr PullRequest) string {
+	var parts []string
+	for _, flag := range extractFeatureFlags(pr) {
+		if flag.Graduated {
+			continue
+		}
+		state := "off"
+		if flag.DefaultOn {
+			state = "on"
+		}
+		parts = append(parts, fmt.Sprintf("behind feature flag %s, default %s", flag.Name, state))
+	}
+
+	if len(parts) == 0 {
+		return ""
+	}
+
+	return "(" + strings.Join(parts, "; ") + ")"
+}
+
+// printFeatureFlagSummary prints a table of the feature flags introduced or
+// graduated by the PRs of the milestone
+func printFeatureFlagSummary(prs []PullRequest) {
+	type row struct {
+		flag FeatureFlag
+		pr   PullRequest
+	}
+
+	var rows []row
+	for _, pr := range prs {
+		for _, flag := range extractFeatureFlags(pr) {
+			rows = append(rows, row{flag, pr})
+		}
+	}
+
+	if len(rows) == 0 {
+		return
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].flag.Name < rows[j].flag.Name
+	})
+
+	fmt.Println("Feature flags:")
+	fmt.Println()
+	fmt.Println("| Flag | Status | Default | PR |")
+	fmt.Println("| --- | --- | --- | --- |")
+	for _, r := range rows {
+		status, state := "Introduced", "off"
+		if r.flag.Graduated {
+			status, state = "Graduated", "-"
+		} else if r.flag.DefaultOn {
+			state = "on"
+		}
+		fmt.Printf("| %s | %s | %s | %s#%d |\n", r.flag.Name, status, state, repoNameFromURL(r.pr.RepoURL), r.pr.Number)
+	}
+	fmt.Println()
+}
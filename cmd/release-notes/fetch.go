@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// maxConcurrentRequests limits the API requests made at the same time
+// when fetching several repositories
+const maxConcurrentRequests = 4
+
+// includeUnmerged keeps the PRs closed without merging, which are abandoned
+// changes, in the notes
+var includeUnmerged bool
+
+// fetchMilestones gets the open milestones of the repositories concurrently,
+// in the order of the repositories, failing if any can't be listed
+func fetchMilestones(repos []Repository) ([][]Milestone, error) {
+	milestoneSets := make([][]Milestone, len(repos))
+
+	var g errgroup.Group
+	g.SetLimit(maxConcurrentRequests)
+	for i, repo := range repos {
+		g.Go(func() (err error) {
+			defer recoverGoroutine(&err)
+
+			milestones, err := repo.forge().Milestones(repo)
+			if err != nil {
+				return fmt.Errorf("error getting milestones of %s: %w", repo.Name, err)
+			}
+			// Add repo URL to each milestone
+			for j := range milestones {
+				milestones[j].RepoURL = repo.URL()
+			}
+			milestoneSets[i] = milestones
+			return nil
+		})
+	}
+
+	return milestoneSets, g.Wait()
+}
+
+// fetchPRs gets the PRs with release notes of the milestones concurrently,
+// in the order of the milestones, keeping the ones with the --label and
+// without the --exclude-label labels and applying to the --sku SKU, and
+// leaving out the ones closed without merging unless --include-unmerged. The
+// error of each milestone is returned
+// along with the PRs of the others.
+func fetchPRs(milestones []Milestone) ([]PullRequest, []error) {
+	prSets := make([][]PullRequest, len(milestones))
+	errs := make([]error, len(milestones))
+
+	var g errgroup.Group
+	g.SetLimit(maxConcurrentRequests)
+	for i, milestone := range milestones {
+		g.Go(func() error {
+			defer recoverGoroutine(&errs[i])
+
+			repo := findRepository(milestone.RepoURL)
+			prSets[i], errs[i] = repo.forge().PullRequests(repo, milestone)
+			return nil
+		})
+	}
+	g.Wait()
+
+	var prs []PullRequest
+	for _, prSet := range prSets {
+		prs = append(prs, prSet...)
+	}
+	if !includeUnmerged {
+		prs = excludeUnmergedPRs(prs)
+	}
+	prs = filterPRsByLabels(prs, requiredLabels, excludedLabels)
+	return filterPRsBySKU(prs, skuFilter), errs
+}
+
+// excludeUnmergedPRs leaves out the PRs closed without merging. The open ones
+// are kept, as they can still be merged before the release.
+func excludeUnmergedPRs(prs []PullRequest) []PullRequest {
+	var result []PullRequest
+	for _, pr := range prs {
+		if pr.State == "closed" && pr.MergedAt.IsZero() {
+			logger.Info("Skipping PR closed without merging, include it with --include-unmerged", "pr", prKey(pr), "title", pr.Title)
+			continue
+		}
+		result = append(result, pr)
+	}
+	return result
+}
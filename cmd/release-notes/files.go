@@ -0,0 +1,313 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/jespino/github-mm-release-notes/github"
+)
+
+// monorepoAreas maps the top-level directories of the mattermost monorepo to
+// the shipping component they belong to
+var monorepoAreas = []struct {
+	Name   string
+	Prefix string
+}{
+	{Name: "Server", Prefix: "server/"},
+	{Name: "Webapp", Prefix: "webapp/"},
+	{Name: "API", Prefix: "api/"},
+}
+
+// otherArea is used for monorepo PRs that don't touch any known area
+const otherArea = "Other"
+
+// AreaGroup holds the PRs attributed to a single area
+type AreaGroup struct {
+	Area string
+	PRs  []PullRequest
+}
+
+// getPRFiles returns the paths of the files changed by a pull request
+func getPRFiles(repoURL string, number int) ([]string, error) {
+	url := fmt.Sprintf("%s/pulls/%d/files?per_page=100", repoURL, number)
+
+	files, err := github.GetAllPages[struct {
+		Filename string `json:"filename"`
+	}](apiClient, url)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(files))
+	for _, file := range files {
+		paths = append(paths, file.Filename)
+	}
+
+	return paths, nil
+}
+
+// fetchPRFiles populates the Files field of the PRs in place, skipping the
+// ones that were already fetched. The PRs of repositories not hosted on
+// GitHub are left without files.
+func fetchPRFiles(prs []PullRequest) error {
+	for i := range prs {
+		if prs[i].Files != nil {
+			continue
+		}
+		if !isGitHubRepo(prs[i].RepoURL) {
+			prs[i].Files = []string{}
+			continue
+		}
+
+		files, err := getPRFiles(prs[i].RepoURL, prs[i].Number)
+		if err != nil {
+			return fmt.Errorf("error getting files for PR #%d: %w", prs[i].Number, err)
+		}
+		prs[i].Files = files
+	}
+
+	return nil
+}
+
+// fetchPRDetails populates the fields of the PRs only available from the
+// pulls API (diff stats and merge time) in place, skipping the ones that were
+// already fetched, or not hosted on GitHub
+func fetchPRDetails(prs []PullRequest) error {
+	for i := range prs {
+		if prs[i].detailsFetched || !isGitHubRepo(prs[i].RepoURL) {
+			continue
+		}
+
+		url := fmt.Sprintf("%s/pulls/%d", prs[i].RepoURL, prs[i].Number)
+
+		var details struct {
+			Additions int        `json:"additions"`
+			Deletions int        `json:"deletions"`
+			MergedAt  *time.Time `json:"merged_at"`
+		}
+		if err := apiClient.GetJSON(url, &details); err != nil {
+			return fmt.Errorf("error getting details for PR #%d: %w", prs[i].Number, err)
+		}
+
+		prs[i].Additions = details.Additions
+		prs[i].Deletions = details.Deletions
+		if details.MergedAt != nil {
+			prs[i].MergedAt = *details.MergedAt
+		}
+		prs[i].detailsFetched = true
+	}
+
+	return nil
+}
+
+// prAreas returns the areas a PR belongs to. Monorepo PRs are attributed by
+// their changed paths, PRs from other repositories by the repository name.
+func prAreas(pr PullRequest) []string {
+	if pr.RepoURL != mattermostRepoURL {
+		return []string{repoNameFromURL(pr.RepoURL)}
+	}
+
+	var areas []string
+	for _, area := range monorepoAreas {
+		for _, file := range pr.Files {
+			if strings.HasPrefix(file, area.Prefix) {
+				areas = append(areas, area.Name)
+				break
+			}
+		}
+	}
+
+	if len(areas) == 0 {
+		return []string{otherArea}
+	}
+
+	return areas
+}
+
+// groupPRsByArea splits the PRs into per-area groups. A PR touching several
+// areas is listed in each of them. Groups keep the order of monorepoAreas,
+// followed by other repositories and finally the "Other" area.
+func groupPRsByArea(prs []PullRequest) []AreaGroup {
+	var order []string
+	for _, area := range monorepoAreas {
+		order = append(order, area.Name)
+	}
+
+	groups := make(map[string][]PullRequest)
+	for _, pr := range prs {
+		for _, area := range prAreas(pr) {
+			if _, ok := groups[area]; !ok && !containsString(order, area) && area != otherArea {
+				order = append(order, area)
+			}
+			groups[area] = append(groups[area], pr)
+		}
+	}
+	order = append(order, otherArea)
+
+	var result []AreaGroup
+	for _, area := range order {
+		if len(groups[area]) > 0 {
+			result = append(result, AreaGroup{Area: area, PRs: groups[area]})
+		}
+	}
+
+	return result
+}
+
+// pathPatterns is a flag with comma-separated path globs, compiled when the
+// flag is parsed so invalid patterns are rejected before fetching anything
+type pathPatterns struct {
+	value string
+	exprs []*regexp.Regexp
+}
+
+func (p *pathPatterns) String() string {
+	return p.value
+}
+
+func (p *pathPatterns) Set(value string) error {
+	exprs, err := compilePathPatterns(splitList(value))
+	if err != nil {
+		return err
+	}
+
+	p.value = value
+	p.exprs = exprs
+	return nil
+}
+
+// filterPRsByPaths keeps the PRs that change at least one file matching the
+// include patterns (or any file when there are none), ignoring files matching
+// the exclude patterns. PRs must have their files fetched beforehand.
+func filterPRsByPaths(prs []PullRequest, include, exclude []*regexp.Regexp) []PullRequest {
+	var result []PullRequest
+	for _, pr := range prs {
+		for _, file := range pr.Files {
+			if matchAnyPath(exclude, file) {
+				continue
+			}
+			if len(include) == 0 || matchAnyPath(include, file) {
+				result = append(result, pr)
+				break
+			}
+		}
+	}
+
+	return result
+}
+
+// matchAnyPath reports whether the path matches any of the compiled patterns
+func matchAnyPath(exprs []*regexp.Regexp, path string) bool {
+	for _, re := range exprs {
+		if re.MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// compilePathPatterns compiles glob patterns with compilePathPattern
+func compilePathPatterns(patterns []string) ([]*regexp.Regexp, error) {
+	var exprs []*regexp.Regexp
+	for _, pattern := range patterns {
+		re, err := compilePathPattern(pattern)
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, re)
+	}
+	return exprs, nil
+}
+
+// compilePathPattern compiles a glob pattern into a regexp matching paths.
+// Besides the "*", "?" and "[...]" wildcards of path.Match, "**" matches
+// across directories, and a pattern ending in "/" matches everything below
+// that directory.
+func compilePathPattern(pattern string) (*regexp.Regexp, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("invalid path pattern %q: %w", pattern, err)
+	}
+	if strings.HasSuffix(pattern, "/") {
+		pattern += "**"
+	}
+
+	var expr strings.Builder
+	expr.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			expr.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			expr.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			expr.WriteString("[^/]*")
+		case pattern[i] == '?':
+			expr.WriteString("[^/]")
+		case pattern[i] == '[':
+			end := i + 1 + strings.IndexByte(pattern[i+1:], ']')
+			expr.WriteString(pattern[i : end+1])
+			i = end
+		case pattern[i] == '\\' && i+1 < len(pattern):
+			i++
+			expr.WriteString(regexp.QuoteMeta(string(pattern[i])))
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(pattern[i])))
+		}
+	}
+	expr.WriteString("$")
+
+	re, err := regexp.Compile(expr.String())
+	if err != nil {
+		return nil, fmt.Errorf("invalid path pattern %q: %w", pattern, err)
+	}
+	return re, nil
+}
+
+// splitList splits a comma-separated flag value, dropping empty items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// repoNameFromURL returns the owner/repo name for a repository API URL
+func repoNameFromURL(repoURL string) string {
+	if strings.HasPrefix(repoURL, githubAPIURL+"/repos/") {
+		return strings.TrimPrefix(repoURL, githubAPIURL+"/repos/")
+	}
+	for _, repo := range config.Repositories {
+		if repo.URL() == repoURL {
+			return repo.Name
+		}
+	}
+	return repoURL
+}
+
+// repoURLFromName returns the repository API URL for an owner/repo name
+func repoURLFromName(name string) string {
+	for _, repo := range config.Repositories {
+		if repo.Name == name {
+			return repo.URL()
+		}
+	}
+	return githubForge{}.APIURL(Repository{Name: name})
+}
+
+// containsString reports whether s is in list
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"strings"
+	"time"
+)
+
+// Global flags
+var (
+	useClaudeFormat       bool
+	claudeToken           string
+	useAreas              bool
+	includePaths          pathPatterns
+	excludePaths          pathPatterns
+	showImpact            bool
+	sortBySize            bool
+	checklistFile         string
+	outputFormat          string
+	testHeadings          string
+	featureLabels         string
+	galleryFile           string
+	highlightLabels       string
+	showFlags             bool
+	showSettings          bool
+	showDevSections       bool
+	showPerformance       bool
+	showBenchmarks        bool
+	showA11y              bool
+	showKnownIssues       bool
+	showCarryover         bool
+	showNoteHistory       bool
+	reviewCutoff          string
+	publishTag            string
+	releaseRepo           string
+	notesFile             string
+	publishDraft          bool
+	forcePublish          bool
+	assetFiles            stringList
+	rcNumber              int
+	sinceTag              string
+	snapshotDir           string
+	milestoneFlag         string // The --milestone value when a single milestone is given
+	milestoneFlags        stringList
+	shortLinks            bool
+	doctorCommand         string
+	verbose               bool
+	distDir               string
+	bundlePath            string
+	releaseSetFile        string
+	repoFlag              string
+	repoGroup             string
+	prRefs                stringList
+	watchInterval         time.Duration
+	notifyURL             string
+	showStats             bool
+	fixTerms              bool
+	allowFlagged          bool
+	redactFlagged         bool
+	outputFile            string
+	groupByType           bool
+	pinFile               string
+	manualEntriesFile     string
+	latestMilestone       bool
+	checkMissing          bool
+	commitNotes           bool
+	showRateLimit         bool
+	templateFile          string
+	anonymizeOutput       bool
+	publishReleaseTag     string
+	exportDir             string
+	showContributors      bool
+	firstTimeContributors bool
+	dedupMode             string
+	dedupSimilarity       float64
+	resolveRefLinks       bool
+	inlineRefTitles       bool
+	configFile            string
+)
+
+// stringList is a flag that can be given several times
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// Supported values for the --format flag
+const (
+	formatText         = "text"
+	formatQA           = "qa"
+	formatAnnouncement = "announcement"
+	formatMarkdown     = "markdown"
+)
+
+var outputFormats = []string{formatText, formatQA, formatAnnouncement, formatMarkdown, formatHTML}
+
+// reviewCutoffTime is the parsed --review-cutoff date
+var reviewCutoffTime time.Time
+
+// getGitHubToken returns the GitHub API token from available sources in order of precedence:
+// 1. Command-line flag
+// 2. Environment variable
+// 3. Default token defined in the code
+func getGitHubToken() string {
+	var flagToken string
+	flag.StringVar(&flagToken, "token", "", "GitHub API token")
+	flag.StringVar(&gitlabToken, "gitlab-token", "", "GitLab API token for the repositories hosted on GitLab (default $GITLAB_TOKEN)")
+	flag.StringVar(&giteaToken, "gitea-token", "", "Gitea API token for the repositories hosted on Gitea or Forgejo (default $GITEA_TOKEN)")
+	flag.StringVar(&mattermostToken, "mattermost-token", "", "Mattermost access token to post the release notes with --mattermost-url (default $MATTERMOST_TOKEN)")
+	flag.BoolVar(&useClaudeFormat, "claude", false, "Use Claude AI to format release notes into categories")
+	flag.StringVar(&claudeToken, "claudetoken", "", "Anthropic API token for Claude AI")
+	flag.BoolVar(&useAreas, "areas", false, "Split release notes into per-area sub-changelogs (server, webapp, api) for the mattermost monorepo")
+	flag.Var(&includePaths, "paths", "Only include PRs changing files matching these comma-separated globs (e.g. \"server/**\")")
+	flag.BoolVar(&includeUnmerged, "include-unmerged", false, "Include the PRs closed without merging, left out by default")
+	flag.BoolVar(&splitDelivery, "split-delivery", false, "Render a self-hosted variant of the notes, titled by version, and a Cloud one, titled by date, each without the changes only shipped to the other, to <output>-self-hosted and <output>-cloud with --output")
+	flag.StringVar(&skuFilter, "sku", "", "Only include the changes applying to this SKU of the config file, e.g. professional, leaving out the ones tagged by their labels with other SKUs")
+	flag.Var(&requiredLabels, "label", "Only include the PRs with this label, besides the release note one (can be repeated, PRs must have all of them)")
+	flag.Var(&excludedLabels, "exclude-label", "Leave out the PRs with this label, e.g. do-not-merge (can be repeated)")
+	flag.Var(&excludePaths, "exclude-paths", "Ignore changed files matching these comma-separated globs when filtering by paths")
+	flag.BoolVar(&showImpact, "impact", false, "Annotate each PR with an impact hint (S/M/L) based on its diff size")
+	flag.BoolVar(&sortBySize, "sort-by-size", false, "List the largest changes first")
+	flag.StringVar(&outputFormat, "format", formatText, "Output format: text, qa to include the test steps of each PR, announcement for a blog post draft of the new features, markdown for the changelog docs, or html for a self-contained page")
+	flag.StringVar(&testHeadings, "test-headings", "Testing,QA Test Steps", "Comma-separated PR body headings holding the test steps, used by --format=qa")
+	flag.StringVar(&featureLabels, "feature-labels", "kind/feature", "Comma-separated labels marking a PR as a new feature")
+	flag.StringVar(&highlightLabels, "highlight-labels", "highlight", "Comma-separated labels marking a PR as a release highlight, used by --format=announcement")
+	flag.StringVar(&galleryFile, "gallery", "", "Write a \"What's new visually\" gallery with the images of the feature PRs to this file (.html for HTML, Markdown otherwise)")
+	flag.BoolVar(&showFlags, "feature-flags", false, "Annotate notes of features behind feature flags and summarize the flags introduced or graduated")
+	flag.BoolVar(&showSettings, "settings", false, "List the new and changed configuration settings in a table for admins")
+	flag.BoolVar(&showDevSections, "developer-sections", false, "Add the sections for developers, such as API and websocket event changes, defined by label and path rules")
+	flag.BoolVar(&showPerformance, "performance", false, "Add a section listing the PRs labeled performance")
+	flag.BoolVar(&showBenchmarks, "benchmarks", false, "Attach the benchmark deltas found in the PR descriptions to the performance section")
+	flag.BoolVar(&showA11y, "accessibility", false, "Add a section listing the PRs labeled accessibility")
+	flag.BoolVar(&showKnownIssues, "known-issues", false, "Add a section listing the open bugs of the milestone and the open issues labeled known-issue")
+	flag.BoolVar(&showContributors, "contributors", false, "Add a \"Thanks to our contributors\" section listing the authors of the PRs")
+	flag.BoolVar(&firstTimeContributors, "first-time-contributors", false, "Add the contributors section, highlighting the authors whose first merged PR is in the release")
+	flag.BoolVar(&showCarryover, "carryover", false, "Report the bugs moved out of the milestone, found through their timeline")
+	flag.BoolVar(&showNoteHistory, "note-history", false, "Show when the release-note label was added to each PR and when its description was last edited")
+	flag.StringVar(&reviewCutoff, "review-cutoff", "", "Docs review cutoff date (YYYY-MM-DD); notes labeled or edited later are flagged for re-review, used by --note-history")
+	flag.StringVar(&publishTag, "github-release", "", "Tag of the GitHub Release to create or update, used by the publish command")
+	flag.StringVar(&releaseRepo, "release-repo", "mattermost/mattermost", "Repository of the GitHub Release, used by the publish command and --publish-release")
+	flag.StringVar(&mattermostWebhookURL, "mattermost-webhook-url", "", "Post the generated release notes to this Mattermost incoming webhook")
+	flag.StringVar(&mattermostURL, "mattermost-url", "", "Post the generated release notes to --mattermost-channel on this Mattermost server, through the REST API")
+	flag.StringVar(&mattermostChannel, "mattermost-channel", "", "Mattermost channel to post the release notes to, as its ID or team/channel names")
+	flag.StringVar(&publishReleaseTag, "publish-release", "", "Create or update the GitHub Release of this tag in --release-repo with the generated notes as its body")
+	flag.StringVar(&notesFile, "notes-file", "", "File with the notes to publish, used by the publish command")
+	flag.BoolVar(&publishDraft, "draft", false, "Create the GitHub Release as a draft, used by the publish command and --publish-release")
+	flag.BoolVar(&forcePublish, "force", false, "Allow the publish command and --publish-release to overwrite the notes of an already published release, or to publish notes whose PR descriptions changed since the extraction")
+	flag.BoolVar(&allowFlagged, "allow-flagged", false, "Publish notes containing profanity, e-mail addresses or internal URLs, used by the publish command and --publish-release")
+	flag.BoolVar(&redactFlagged, "redact-flagged", false, "Redact the profanity, e-mail addresses and internal URLs found in the notes before publishing, used by the publish command and --publish-release")
+	flag.Var(&assetFiles, "asset", "File to attach to the GitHub Release, used by the publish command and --publish-release (can be repeated)")
+	flag.IntVar(&rcNumber, "rc", 0, "Release candidate number: label the notes as that RC, only include PRs merged since the previous RC tag, and publish as a prerelease")
+	flag.StringVar(&sinceTag, "since-tag", "", "Tag of the previous release candidate, used by --rc (default <milestone>-rc<N-1>)")
+	flag.BoolVar(&noCache, "no-cache", false, "Don't cache the API responses, nor revalidate the cached ones, fetching everything again")
+	flag.StringVar(&cacheDir, "cache-dir", "", "Directory where the API responses are cached (default ~/.release-notes-extractor/cache)")
+	flag.StringVar(&snapshotDir, "snapshot-dir", "", "Directory where the release candidate snapshots are stored (default ~/.release-notes-extractor/snapshots)")
+	flag.Var(&milestoneFlags, "milestone", "Milestone title, selected without prompting, also used by the assemble-ga and bundle export commands. Give it several times, or as a glob like \"v9.*\", for a single document with the notes of several milestones")
+	flag.BoolVar(&checkMissing, "check-missing", false, "List the merged PRs of the milestone, labeled or not, without a release note in their description instead of the notes, exiting with an error if any")
+	flag.BoolVar(&commitNotes, "commit-notes", false, "Take the release note of the PRs without one in their description from the Release-Note: trailer of their merge commit message, for squash-merging repositories")
+	flag.BoolVar(&showRateLimit, "show-rate-limit", false, "Print the GitHub API quota left at the end of the run")
+	flag.BoolVar(&latestMilestone, "latest", false, "Select the next upcoming open milestone by due date, or the most recently due one, without prompting, for scheduled runs")
+	flag.StringVar(&repoFlag, "repo", "", "Repository to select without prompting: the owner/repo of a configured repository, mattermost/mattermost+mattermost/enterprise, the name of a repository group, or all")
+	flag.StringVar(&repoGroup, "group", "", "Repository group of the config file to select without prompting, e.g. clients")
+	flag.BoolVar(&resolveRefLinks, "resolve-refs", false, "Turn owner/repo#123 references to PRs and issues in the notes into full links")
+	flag.BoolVar(&inlineRefTitles, "ref-titles", false, "Add the title of the referenced PR or issue to the links, used by --resolve-refs")
+	flag.BoolVar(&shortLinks, "short-links", false, "Compress links to GitHub PRs and issues in the notes to the owner/repo#123 form")
+	flag.StringVar(&doctorCommand, "for-command", "", "Command whose permissions the doctor command checks, e.g. publish (default the interactive mode)")
+	flag.StringVar(&distDir, "dist-dir", "release-metadata", "Directory where the release command writes the notes and metadata of the release of this tool")
+	flag.StringVar(&bundlePath, "bundle", "", "Bundle file written by the bundle export command (default <milestone>.bundle.json.gz) and read by the bundle import command")
+	flag.StringVar(&releaseSetFile, "release-set", "", "Write the extracted notes to this annotated YAML file, which can be edited and read back by the render command, and checked for changes by the publish command")
+	flag.StringVar(&backfillFrom, "from", "", "First version of the closed milestones to extract, e.g. v9.0, used by the backfill command, or the installed version with the upgrade-notes command")
+	flag.StringVar(&backfillTo, "to", "", "Last version of the closed milestones to extract, e.g. v10.2 including its patch releases, used by the backfill command, or the target version with the upgrade-notes command")
+	flag.IntVar(&parallelMilestones, "parallel", maxConcurrentRequests, "Milestones processed at the same time by the backfill command and with several --milestone, reduced to stay within the rate limit left")
+	flag.StringVar(&changelogFile, "file", "", "Markdown changelog imported by the import-changelog command")
+	flag.StringVar(&changelogVersion, "version", "", "Version whose notes the import-changelog command imports, e.g. v9.5")
+	flag.StringVar(&backfillDir, "backfill-dir", "backfill", "Directory of the release sets written by the backfill and import-changelog commands, and read by the upgrade-notes command")
+	flag.Var(&prRefs, "pr", "PR in the owner/repo#123 form, used by the refresh, exclude and restore commands (can be repeated)")
+	flag.BoolVar(&clearComments, "clear", false, "Remove the comments of the entry, used by the annotate command")
+	flag.StringVar(&exclusionReason, "reason", "", "Why the PRs are excluded from the release set, used by the exclude command")
+	flag.DurationVar(&watchInterval, "interval", 5*time.Minute, "Polling interval of the watch command")
+	flag.StringVar(&notifyURL, "notify-url", "", "Incoming webhook URL (Mattermost or Slack) the watch command posts the label changes to")
+	flag.BoolVar(&showStats, "stats", false, "Print the number of entries, words and estimated reading time of each section")
+	flag.BoolVar(&fixTerms, "fix-terms", false, "Replace the terms not following the terminology dictionary with the preferred ones, used by the render command")
+	flag.BoolVar(&verbose, "verbose", false, "Log debug messages, such as every GitHub API request")
+	flag.BoolVar(&useGraphQL, "graphql", false, "Fetch the milestones and PRs of the GitHub repositories with the GraphQL API, in fewer requests than the REST API (requires a token)")
+	flag.StringVar(&apiBaseURL, "api-url", os.Getenv("GITHUB_API_URL"), "Base URL of the GitHub REST API (default "+githubAPIURL+")")
+	flag.StringVar(&configFile, "config", "", "Configuration file (default ~/"+defaultConfigFile+")")
+	flag.BoolVar(&groupByType, "by-type", false, "Group the notes by type of change (breaking changes, deprecations, new features, improvements and bug fixes), from their labels or note prefixes")
+	flag.BoolVar(&markProvisional, "mark-provisional", false, "Mark the notes whose type of change was guessed as provisional, for the reviewers to confirm")
+	flag.StringVar(&manualEntriesFile, "manual-entries", "", "YAML file of hand-written entries, e.g. for changes with no PR, merged into the generated notes")
+	flag.StringVar(&pinFile, "pin-file", "", "File listing PRs in the owner/repo#123 form, one per line, listed first in their sections in that order")
+	flag.BoolVar(&anonymizeOutput, "anonymize", false, "Strip author handles and internal references (ticket IDs, internal URLs and e-mail addresses) from the notes, for a customer-shareable variant")
+	flag.StringVar(&templateFile, "template", "", "Render the release notes through this Go text/template file, with the milestone, repositories and entries as data")
+	flag.StringVar(&outputFile, "output", "", "Write the release notes to this file instead of stdout, keeping the prompts out of it")
+	flag.StringVar(&dedupMode, "dedup", "", "Merge the entries with the same release note, e.g. in mattermost and enterprise, into one linking to all their PRs: exact or fuzzy")
+	flag.Float64Var(&dedupSimilarity, "dedup-similarity", 0.85, "How alike, from 0 to 1, two release notes must be to be merged with --dedup=fuzzy")
+	flag.StringVar(&exportDir, "export-entries", "", "Write a JSON file per entry, with its note, category, links and version, to this directory for the docs tooling")
+	flag.StringVar(&checklistFile, "checklist", "", "Write a Markdown QA checklist of the user-facing changes, grouped by area, to this file")
+	flag.Parse()
+	consoleLogger.Verbose = verbose
+
+	if gitlabToken == "" {
+		gitlabToken = os.Getenv("GITLAB_TOKEN")
+	}
+	if giteaToken == "" {
+		giteaToken = os.Getenv("GITEA_TOKEN")
+	}
+	if mattermostToken == "" {
+		mattermostToken = os.Getenv("MATTERMOST_TOKEN")
+	}
+
+	// Check sources in order of precedence
+	if flagToken != "" {
+		return flagToken
+	}
+
+	if envToken := os.Getenv("GITHUB_TOKEN"); envToken != "" {
+		return envToken
+	}
+
+	return defaultAuthToken
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// followUpRegexp matches references to the PR a follow-up builds on, e.g.
+// "Follow-up to #123", "Followup for mattermost/mattermost#123" or
+// "Follow up of https://github.com/mattermost/mattermost/pull/123"
+var followUpRegexp = regexp.MustCompile(`(?i)follow[- ]?up(?:\s+(?:to|for|of|on))?\s*:?\s*(?:https://github\.com/([\w.-]+/[\w.-]+)/pull/|([\w.-]+/[\w.-]+)?#)(\d+)`)
+
+// followUpTarget returns the number of the PR the given PR follows up on, or
+// 0 if it is not a follow-up of a PR in the same repository
+func followUpTarget(pr PullRequest) int {
+	for _, text := range []string{pr.Title, pr.Body} {
+		matches := followUpRegexp.FindStringSubmatch(text)
+		if matches == nil {
+			continue
+		}
+
+		repo := matches[1]
+		if repo == "" {
+			repo = matches[2]
+		}
+		if repo != "" && !strings.EqualFold(repo, repoNameFromURL(pr.RepoURL)) {
+			continue
+		}
+
+		number, _ := strconv.Atoi(matches[3])
+		if number != pr.Number {
+			return number
+		}
+	}
+
+	return 0
+}
+
+// collapseFollowUps folds follow-up PRs into the entry of the PR they build
+// on when both are in the milestone, so a feature landing in several PRs is
+// listed once with links to all of them. Chains of follow-ups are folded
+// into the first PR.
+func collapseFollowUps(prs []PullRequest) []PullRequest {
+	index := make(map[string]int)
+	for i, pr := range prs {
+		index[pr.RepoURL+"#"+strconv.Itoa(pr.Number)] = i
+	}
+
+	// root follows the follow-up chain up to the first PR in the milestone.
+	// When the chain loops back on itself, the PR with the lowest number in
+	// the loop is the root, so the loop folds the same way wherever it's
+	// entered.
+	root := func(i int) int {
+		var chain []int
+		seen := make(map[int]int)
+		for {
+			seen[i] = len(chain)
+			chain = append(chain, i)
+			target, ok := index[prs[i].RepoURL+"#"+strconv.Itoa(followUpTarget(prs[i]))]
+			if !ok {
+				return i
+			}
+			if start, loop := seen[target]; loop {
+				lowest := chain[start]
+				for _, j := range chain[start:] {
+					if prs[j].Number < prs[lowest].Number {
+						lowest = j
+					}
+				}
+				return lowest
+			}
+			i = target
+		}
+	}
+
+	roots := make([]int, len(prs))
+	for i := range prs {
+		roots[i] = root(i)
+	}
+
+	var result []PullRequest
+	position := make(map[int]int)
+	for i, pr := range prs {
+		if roots[i] == i {
+			position[i] = len(result)
+			result = append(result, pr)
+		}
+	}
+	for i, pr := range prs {
+		if r := roots[i]; r != i {
+			if p, ok := position[r]; ok {
+				result[p].FollowUps = append(result[p].FollowUps, pr.Number)
+			}
+		}
+	}
+
+	return result
+}
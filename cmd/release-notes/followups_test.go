@@ -0,0 +1,72 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCollapseFollowUps(t *testing.T) {
+	repoURL := githubAPIURL + "/repos/o/r"
+	pr := func(number int, title string) PullRequest {
+		return PullRequest{Number: number, Title: title, RepoURL: repoURL}
+	}
+
+	tests := []struct {
+		name      string
+		prs       []PullRequest
+		numbers   []int
+		followUps map[int][]int
+	}{
+		{
+			name:      "follow-up",
+			prs:       []PullRequest{pr(1, "Add foo"), pr(2, "Follow-up to #1")},
+			numbers:   []int{1},
+			followUps: map[int][]int{1: {2}},
+		},
+		{
+			name:      "chain",
+			prs:       []PullRequest{pr(3, "Follow-up to #2"), pr(1, "Add foo"), pr(2, "Follow-up to #1")},
+			numbers:   []int{1},
+			followUps: map[int][]int{1: {3, 2}},
+		},
+		{
+			name:      "follow-up of a PR out of the milestone",
+			prs:       []PullRequest{pr(1, "Add foo"), pr(2, "Follow-up to #9")},
+			numbers:   []int{1, 2},
+			followUps: map[int][]int{},
+		},
+		{
+			name:      "cycle",
+			prs:       []PullRequest{pr(2, "Follow-up to #1"), pr(1, "Follow-up to #2")},
+			numbers:   []int{1},
+			followUps: map[int][]int{1: {2}},
+		},
+		{
+			name:      "longer cycle entered from outside",
+			prs:       []PullRequest{pr(4, "Follow-up to #3"), pr(3, "Follow-up to #5"), pr(5, "Follow-up to #2"), pr(2, "Follow-up to #3")},
+			numbers:   []int{2},
+			followUps: map[int][]int{2: {4, 3, 5}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := collapseFollowUps(test.prs)
+
+			var numbers []int
+			followUps := make(map[int][]int)
+			for _, pr := range result {
+				numbers = append(numbers, pr.Number)
+				if pr.FollowUps != nil {
+					followUps[pr.Number] = pr.FollowUps
+				}
+			}
+			if !reflect.DeepEqual(numbers, test.numbers) {
+				t.Errorf("numbers = %v, want %v", numbers, test.numbers)
+			}
+			if !reflect.DeepEqual(followUps, test.followUps) {
+				t.Errorf("follow-ups = %v, want %v", followUps, test.followUps)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/jespino/github-mm-release-notes/github"
+)
+
+// Forges the repositories can be hosted on
+const (
+	forgeGitHub = "github"
+	forgeGitLab = "gitlab"
+	forgeGitea  = "gitea"
+)
+
+var forges = []string{forgeGitHub, forgeGitLab, forgeGitea}
+
+// Forge is the API of the code hosting service of a repository, listing its
+// milestones and the pull requests with release notes, called merge requests
+// on some forges. The rest of the features use the GitHub API.
+type Forge interface {
+	// APIURL returns the API URL identifying the repository
+	APIURL(repo Repository) string
+	// Milestones returns the open milestones of the repository
+	Milestones(repo Repository) ([]Milestone, error)
+	// PullRequests returns the PRs of a milestone of the repository with
+	// any of its release note labels
+	PullRequests(repo Repository, milestone Milestone) ([]PullRequest, error)
+	// PullRequestURL returns the web URL of a PR of the repository
+	PullRequestURL(repo Repository, number int) string
+}
+
+// forge returns the forge the repository is hosted on
+func (r Repository) forge() Forge {
+	switch r.Forge {
+	case forgeGitLab:
+		return gitlabForge{}
+	case forgeGitea:
+		return giteaForge{}
+	default:
+		return githubForge{}
+	}
+}
+
+// githubForge is the GitHub REST API, or the GraphQL API for the milestones
+// and PRs with --graphql
+type githubForge struct{}
+
+func (githubForge) APIURL(repo Repository) string {
+	return githubAPIURL + "/repos/" + repo.Name
+}
+
+func (githubForge) Milestones(repo Repository) ([]Milestone, error) {
+	if useGraphQL {
+		return getMilestonesGraphQL(repo)
+	}
+	return getMilestones(repo.URL())
+}
+
+func (githubForge) PullRequests(repo Repository, milestone Milestone) ([]PullRequest, error) {
+	if useGraphQL {
+		return getPRsWithReleaseNotesGraphQL(repo, milestone)
+	}
+	return getPRsWithReleaseNotes(repo.URL(), milestone.Number)
+}
+
+func (githubForge) PullRequestURL(repo Repository, number int) string {
+	return fmt.Sprintf("https://github.com/%s/pull/%d", repo.Name, number)
+}
+
+// isGitHubRepo reports whether the repository with the given API URL is
+// hosted on GitHub, whose API the features beyond listing the milestones and
+// PRs rely on
+func isGitHubRepo(repoURL string) bool {
+	_, ok := findRepository(repoURL).forge().(githubForge)
+	return ok
+}
+
+// githubMilestones returns the milestones of the GitHub repositories, for the
+// features only supported on GitHub, warning about the ones left out so a
+// release spanning several forges still produces a single document
+func githubMilestones(milestones []Milestone, feature string) []Milestone {
+	var result []Milestone
+	for _, milestone := range milestones {
+		if !isGitHubRepo(milestone.RepoURL) {
+			logger.Warn("Skipping repository, the feature is only supported on GitHub", "repo", repoNameFromURL(milestone.RepoURL), "feature", feature)
+			continue
+		}
+		result = append(result, milestone)
+	}
+	return result
+}
+
+// authHeader returns the authentication header for a request to the API
+// URL, with the token of the forge it belongs to, or an empty value when
+// there is no token
+func authHeader(apiURL string) (string, string) {
+	for _, repo := range config.Repositories {
+		switch {
+		case repo.Forge == forgeGitLab && strings.HasPrefix(apiURL, gitlabAPIURL(repo)+"/"):
+			return "PRIVATE-TOKEN", gitlabToken
+		case repo.Forge == forgeGitea && strings.HasPrefix(apiURL, giteaAPIURL(repo)+"/"):
+			if giteaToken == "" {
+				return "Authorization", ""
+			}
+			return "Authorization", "token " + giteaToken
+		}
+	}
+
+	if authToken == "" {
+		return "Authorization", ""
+	}
+	return "Authorization", "Bearer " + authToken
+}
+
+// Gets all open milestones from the specified repository
+func getMilestones(repoURL string) ([]Milestone, error) {
+	url := fmt.Sprintf("%s/milestones?state=open", repoURL)
+
+	return github.GetAllPages[Milestone](apiClient, url)
+}
+
+// Gets PRs with "release-note" label for a specific milestone
+func getPRsWithReleaseNotes(repoURL string, milestoneID int) ([]PullRequest, error) {
+	// The labels filter matches PRs with all the labels, so each release
+	// note label of the repository is queried separately
+	var prs []PullRequest
+	seen := make(map[int]bool)
+	for _, label := range releaseNoteLabels(repoURL) {
+		labelURL := fmt.Sprintf("%s/issues?milestone=%d&state=all&labels=%s", repoURL, milestoneID, url.QueryEscape(label))
+
+		labelPRs, err := github.GetAllPages[PullRequest](apiClient, labelURL)
+		if err != nil {
+			return nil, err
+		}
+		for _, pr := range labelPRs {
+			if !seen[pr.Number] {
+				seen[pr.Number] = true
+				prs = append(prs, pr)
+			}
+		}
+	}
+
+	var pullRequests []PullRequest
+	for _, pr := range prs {
+		// Verify if it's a PR (not an issue) and has a milestone
+		if strings.Contains(fmt.Sprintf("%s/pull/%d", repoURL, pr.Number), "pull") && pr.Milestone != nil {
+			pr.RepoURL = repoURL
+			if pr.PullRequest != nil && pr.PullRequest.MergedAt != nil {
+				pr.MergedAt = *pr.PullRequest.MergedAt
+			}
+			pullRequests = append(pullRequests, pr)
+		}
+	}
+
+	return pullRequests, nil
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jespino/github-mm-release-notes/extract"
+)
+
+// Image is a picture embedded in a PR description
+type Image struct {
+	Alt string
+	URL string
+}
+
+var (
+	markdownImageRegexp = regexp.MustCompile(`!\[([^\]]*)\]\(\s*([^)\s]+)[^)]*\)`)
+	htmlImageRegexp     = regexp.MustCompile(`(?i)<img\s[^>]*>`)
+	htmlSrcRegexp       = regexp.MustCompile(`(?i)\ssrc\s*=\s*["']([^"']+)["']`)
+	htmlAltRegexp       = regexp.MustCompile(`(?i)\salt\s*=\s*["']([^"']*)["']`)
+)
+
+// extractImages returns the images embedded in a PR description, both in
+// Markdown and HTML form, in order of appearance
+func extractImages(body string) []Image {
+	type located struct {
+		pos   int
+		image Image
+	}
+	var found []located
+
+	for _, m := range markdownImageRegexp.FindAllStringSubmatchIndex(body, -1) {
+		found = append(found, located{m[0], Image{
+			Alt: body[m[2]:m[3]],
+			URL: body[m[4]:m[5]],
+		}})
+	}
+
+	for _, loc := range htmlImageRegexp.FindAllStringIndex(body, -1) {
+		tag := body[loc[0]:loc[1]]
+		src := htmlSrcRegexp.FindStringSubmatch(tag)
+		if src == nil {
+			continue
+		}
+		image := Image{URL: src[1]}
+		if alt := htmlAltRegexp.FindStringSubmatch(tag); alt != nil {
+			image.Alt = alt[1]
+		}
+		found = append(found, located{loc[0], image})
+	}
+
+	// Restore the order of appearance across both formats
+	sort.SliceStable(found, func(i, j int) bool {
+		return found[i].pos < found[j].pos
+	})
+
+	images := make([]Image, 0, len(found))
+	for _, f := range found {
+		images = append(images, f.image)
+	}
+
+	return images
+}
+
+// writeGallery writes a "What's new visually" appendix with the images of
+// the feature-labeled PRs. The gallery is rendered as HTML when the file has
+// an .html extension and as Markdown otherwise.
+func writeGallery(path string, prs []PullRequest, milestoneTitle string) error {
+	var features []PullRequest
+	for _, pr := range prs {
+		if hasAnyLabel(pr, splitList(featureLabels)) && len(extractImages(pr.Body)) > 0 {
+			features = append(features, pr)
+		}
+	}
+
+	var content []byte
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".html", ".htm":
+		content = renderHTMLGallery(features, milestoneTitle)
+	default:
+		content = renderMarkdownGallery(features, milestoneTitle)
+	}
+
+	return os.WriteFile(path, content, 0644)
+}
+
+// renderMarkdownGallery renders the gallery as a Markdown document
+func renderMarkdownGallery(prs []PullRequest, milestoneTitle string) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# What's new visually in %s\n", milestoneTitle)
+
+	for _, pr := range prs {
+		fmt.Fprintf(&buf, "\n## %s (%s#%d)\n\n", pr.Title, repoNameFromURL(pr.RepoURL), pr.Number)
+		if releaseNote := prReleaseNote(pr); extract.HasReleaseNote(releaseNote) {
+			fmt.Fprintf(&buf, "%s\n\n", releaseNote)
+		}
+		for _, image := range extractImages(pr.Body) {
+			fmt.Fprintf(&buf, "![%s](%s)\n", image.Alt, image.URL)
+		}
+	}
+
+	return buf.Bytes()
+}
+
+// renderHTMLGallery renders the gallery as a self-contained HTML page
+func renderHTMLGallery(prs []PullRequest, milestoneTitle string) []byte {
+	title := html.EscapeString("What's new visually in " + milestoneTitle)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>%s</title>
+<style>
+body { font-family: sans-serif; max-width: 960px; margin: 0 auto; padding: 1em; }
+.gallery img { max-width: 100%%; border: 1px solid #ddd; margin: 0.5em 0; }
+</style>
+</head>
+<body>
+<h1>%s</h1>
+`, title, title)
+
+	for _, pr := range prs {
+		fmt.Fprintf(&buf, "<section>\n<h2>%s (%s#%d)</h2>\n",
+			html.EscapeString(pr.Title), html.EscapeString(repoNameFromURL(pr.RepoURL)), pr.Number)
+		if releaseNote := prReleaseNote(pr); extract.HasReleaseNote(releaseNote) {
+			fmt.Fprintf(&buf, "<p>%s</p>\n", html.EscapeString(releaseNote))
+		}
+		buf.WriteString("<div class=\"gallery\">\n")
+		for _, image := range extractImages(pr.Body) {
+			fmt.Fprintf(&buf, "<img src=\"%s\" alt=\"%s\">\n", html.EscapeString(image.URL), html.EscapeString(image.Alt))
+		}
+		buf.WriteString("</div>\n</section>\n")
+	}
+
+	buf.WriteString("</body>\n</html>\n")
+	return buf.Bytes()
+}
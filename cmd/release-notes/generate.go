@@ -0,0 +1,287 @@
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// generateReleaseNotes prints the release notes of the PRs of a milestone,
+// along with the extra sections and files requested by the flags. The
+// milestones are the ones matching the selection in each repository.
+func generateReleaseNotes(prs []PullRequest, milestoneTitle string, targetMilestones []Milestone, repoName string) error {
+	// Print information for each PR and its release notes
+	if len(prs) == 0 {
+		fmt.Println("No PRs with 'release-note' label found in this milestone.")
+		return nil
+	}
+
+	// Squash-merging repositories may keep the notes in the commit messages
+	if commitNotes {
+		if err := fetchCommitNotes(prs); err != nil {
+			return fmt.Errorf("error getting merge commit notes: %w", err)
+		}
+	}
+
+	if anonymizeOutput {
+		if err := anonymizePRs(prs); err != nil {
+			return fmt.Errorf("error anonymizing the notes: %w", err)
+		}
+	}
+
+	// Leave reverted changes out of the notes
+	prs, revertedChanges := separateReverts(prs)
+
+	// Authors of PRs without a release note contributed too
+	var contributors []Contributor
+	if showContributors || firstTimeContributors {
+		contributors = collectContributors(prs)
+		if firstTimeContributors {
+			if err := markFirstTimeContributors(contributors); err != nil {
+				return fmt.Errorf("error finding first-time contributors: %w", err)
+			}
+		}
+	}
+
+	// Leave out the PRs explicitly without a release note
+	prs, noneNotes := separateNoneNotes(prs)
+
+	// List follow-up PRs under the PR they build on
+	prs = collapseFollowUps(prs)
+
+	// List the same note landing in several repositories once
+	prs, err := applyDedup(prs)
+	if err != nil {
+		return fmt.Errorf("error merging duplicate notes: %w", err)
+	}
+
+	if useClaudeFormat {
+		if claudeToken == "" {
+			claudeToken = os.Getenv("ANTHROPIC_API_KEY")
+			if claudeToken == "" {
+				return fmt.Errorf("no Anthropic API token provided, set one with the --claudetoken flag or the ANTHROPIC_API_KEY environment variable")
+			}
+		}
+	}
+
+	// Filter PRs by the files they change
+	if includePaths.exprs != nil || excludePaths.exprs != nil {
+		if err := fetchPRFiles(prs); err != nil {
+			return fmt.Errorf("error getting changed files: %w", err)
+		}
+
+		prs = filterPRsByPaths(prs, includePaths.exprs, excludePaths.exprs)
+		if len(prs) == 0 {
+			fmt.Println("No PRs with 'release-note' label changing the selected paths found in this milestone.")
+			return nil
+		}
+	}
+
+	notesTitle := milestoneHeader(milestoneTitle)
+	if rcNumber > 0 {
+		notesTitle = fmt.Sprintf("%s (Release Candidate %d)", milestoneDisplayName(milestoneTitle), rcNumber)
+
+		// Only keep the changes merged since the previous release candidate
+		previousTag := sinceTag
+		if previousTag == "" && rcNumber > 1 {
+			previousTag = rcTag(milestoneTitle, rcNumber-1)
+		}
+		if previousTag != "" {
+			var err error
+			if prs, err = filterPRsMergedSinceTag(prs, previousTag); err != nil {
+				return fmt.Errorf("error filtering PRs merged since the previous tag %s: %w", previousTag, err)
+			}
+			logger.Info("Only including PRs merged since the previous tag", "tag", previousTag)
+		}
+	}
+
+	// Hand-written entries are added after the filters, which only apply to
+	// the PRs
+	if manualEntriesFile != "" {
+		manualPRs, err := loadManualEntries(manualEntriesFile)
+		if err != nil {
+			return fmt.Errorf("error loading manual entries: %w", err)
+		}
+		if anonymizeOutput {
+			if err := anonymizePRs(manualPRs); err != nil {
+				return fmt.Errorf("error anonymizing the notes: %w", err)
+			}
+		}
+		prs = append(prs, manualPRs...)
+	}
+
+	// Get the diff stats used for impact hints and sorting
+	if showImpact || sortBySize {
+		if err := fetchPRDetails(prs); err != nil {
+			return fmt.Errorf("error getting diff stats: %w", err)
+		}
+
+		if sortBySize {
+			sortPRsBySize(prs)
+		}
+	}
+
+	// Headline features go first in their sections
+	if err := applyPinnedOrder(prs); err != nil {
+		return fmt.Errorf("error pinning PRs: %w", err)
+	}
+
+	if checklistFile != "" {
+		if err := fetchPRFiles(prs); err != nil {
+			return fmt.Errorf("error getting changed files: %w", err)
+		}
+
+		if err := writeChecklist(checklistFile, prs, notesTitle); err != nil {
+			return fmt.Errorf("error writing QA checklist: %w", err)
+		}
+		logger.Info("QA checklist written", "file", checklistFile)
+	}
+
+	if galleryFile != "" {
+		if err := writeGallery(galleryFile, prs, notesTitle); err != nil {
+			return fmt.Errorf("error writing gallery: %w", err)
+		}
+		logger.Info("Gallery written", "file", galleryFile)
+	}
+
+	if exportDir != "" {
+		count, err := exportEntries(exportDir, prs, milestoneTitle)
+		if err != nil {
+			return fmt.Errorf("error exporting entries: %w", err)
+		}
+		logger.Info("Entries exported", "count", count, "dir", exportDir)
+	}
+
+	if showNoteHistory {
+		if err := fetchNoteHistory(prs); err != nil {
+			return fmt.Errorf("error getting note history: %w", err)
+		}
+	}
+
+	if rcNumber > 0 {
+		path, err := saveRCSnapshot(milestoneTitle, rcNumber, prs)
+		if err != nil {
+			return fmt.Errorf("error saving release candidate snapshot: %w", err)
+		}
+		logger.Info("Release candidate snapshot saved", "file", path)
+	}
+
+	if releaseSetFile != "" {
+		// Keep the files in the release set to render it by area later
+		if useAreas {
+			if err := fetchPRFiles(prs); err != nil {
+				return fmt.Errorf("error getting changed files: %w", err)
+			}
+		}
+		if err := writeReleaseSet(releaseSetFile, buildReleaseSet(prs, milestoneTitle, notesTitle, repoName)); err != nil {
+			return fmt.Errorf("error writing release set: %w", err)
+		}
+		logger.Info("Release set written", "file", releaseSetFile)
+	}
+
+	restoreOutput := func() {}
+	if outputFile != "" && !splitDelivery {
+		restore, err := redirectOutput(outputFile)
+		if err != nil {
+			return fmt.Errorf("error creating output file: %w", err)
+		}
+		defer restore()
+		restoreOutput = restore
+	}
+
+	// The notes printed from here on are published as they are
+	var finishCapture func() string
+	if publishReleaseTag != "" || postToMattermost() {
+		var err error
+		if finishCapture, err = captureOutput(); err != nil {
+			return fmt.Errorf("error capturing the notes to publish: %w", err)
+		}
+		defer finishCapture()
+	}
+
+	if splitDelivery {
+		if err := printDeliveryVariants(prs, milestoneTitle, changeLogTypeFor(repoName)); err != nil {
+			return err
+		}
+	} else if err := printReleaseNotesByArea(prs, notesTitle, changeLogTypeFor(repoName)); err != nil {
+		return err
+	}
+
+	if showSettings {
+		// Changed files are needed to detect config schema edits
+		if err := fetchPRFiles(prs); err != nil {
+			return fmt.Errorf("error getting changed files: %w", err)
+		}
+		printSettingChanges(collectSettingChanges(prs))
+	}
+
+	if showDevSections {
+		if err := fetchPRFiles(prs); err != nil {
+			return fmt.Errorf("error getting changed files: %w", err)
+		}
+		for _, section := range config.DeveloperSections {
+			printSection(section, prs, godocLinks)
+		}
+	}
+
+	if showPerformance {
+		section := Section{Title: "Performance improvements", Labels: config.PerformanceLabels}
+		var details func(PullRequest) []string
+		if showBenchmarks {
+			details = func(pr PullRequest) []string { return extractBenchmarkDeltas(pr.Body) }
+		}
+		printSection(section, prs, details)
+	}
+
+	if showA11y {
+		printSection(Section{Title: "Accessibility", Labels: config.AccessibilityLabels}, prs, nil)
+	}
+
+	if showFlags {
+		printFeatureFlagSummary(prs)
+	}
+
+	if showKnownIssues {
+		issues, err := getKnownIssues(githubMilestones(targetMilestones, "--known-issues"))
+		if err != nil {
+			return fmt.Errorf("error getting known issues: %w", err)
+		}
+		printKnownIssues(issues)
+	}
+
+	// The reports for the reviewers printed from here on aren't published
+	var notes string
+	if finishCapture != nil {
+		notes = finishCapture()
+	}
+
+	if showCarryover {
+		bugs, err := getCarriedOverBugs(githubMilestones(targetMilestones, "--carryover"))
+		if err != nil {
+			return fmt.Errorf("error getting deferred bugs: %w", err)
+		}
+		printCarriedOverBugs(bugs)
+	}
+
+	printContributors(contributors)
+	printRevertedChanges(revertedChanges)
+	printNoneNotes(noneNotes)
+
+	if finishCapture != nil {
+		restoreOutput()
+		fmt.Println()
+
+		if publishReleaseTag != "" {
+			if err := publishRelease(publishReleaseTag, notes); err != nil {
+				return fmt.Errorf("error publishing the release: %w", err)
+			}
+		}
+
+		if postToMattermost() {
+			if err := postMattermostNotes(notes); err != nil {
+				return fmt.Errorf("error posting to Mattermost: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jespino/github-mm-release-notes/github"
+)
+
+// giteaToken authenticates the requests to Gitea and Forgejo, from
+// --gitea-token or the GITEA_TOKEN environment variable
+var giteaToken string
+
+// giteaMilestone is a milestone of the Gitea API, with the same fields as
+// on GitHub but the number
+type giteaMilestone struct {
+	ID int `json:"id"`
+	Milestone
+}
+
+// giteaIssue is an issue of the Gitea API, which lists the pull requests as
+// issues too, like GitHub
+type giteaIssue struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	Body   string `json:"body"`
+	Labels []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+	User struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	State       string    `json:"state"`
+	CreatedAt   time.Time `json:"created_at"`
+	PullRequest *struct {
+		MergedAt *time.Time `json:"merged_at"`
+	} `json:"pull_request"`
+}
+
+// giteaForge is the API of Gitea and Forgejo, self-hosted forges exposing an
+// API close to GitHub's under /api/v1
+type giteaForge struct{}
+
+// giteaAPIURL returns the URL of the REST API of the Gitea instance of a
+// repository
+func giteaAPIURL(repo Repository) string {
+	return strings.TrimSuffix(repo.BaseURL, "/") + "/api/v1"
+}
+
+func (giteaForge) APIURL(repo Repository) string {
+	return giteaAPIURL(repo) + "/repos/" + repo.Name
+}
+
+func (giteaForge) Milestones(repo Repository) ([]Milestone, error) {
+	// Milestones have no number within the repository, the ID is used instead
+	milestones, err := github.GetAllPages[giteaMilestone](apiClient, repo.URL()+"/milestones?state=open")
+	if err != nil {
+		return nil, err
+	}
+
+	var result []Milestone
+	for _, m := range milestones {
+		milestone := m.Milestone
+		milestone.Number = m.ID
+		result = append(result, milestone)
+	}
+	return result, nil
+}
+
+func (giteaForge) PullRequests(repo Repository, milestone Milestone) ([]PullRequest, error) {
+	var prs []PullRequest
+	seen := make(map[int]bool)
+	for _, label := range releaseNoteLabels(repo.URL()) {
+		issuesURL := fmt.Sprintf("%s/issues?state=all&type=pulls&milestones=%s&labels=%s", repo.URL(), url.QueryEscape(milestone.Title), url.QueryEscape(label))
+		issues, err := github.GetAllPages[giteaIssue](apiClient, issuesURL)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, issue := range issues {
+			if issue.PullRequest == nil || seen[issue.Number] {
+				continue
+			}
+			seen[issue.Number] = true
+
+			pr := PullRequest{Number: issue.Number, Title: issue.Title, Body: issue.Body, Labels: issue.Labels, User: issue.User, State: issue.State, CreatedAt: issue.CreatedAt, RepoURL: repo.URL()}
+			pr.Milestone = &struct {
+				Number int `json:"number"`
+			}{Number: milestone.Number}
+			if issue.PullRequest.MergedAt != nil {
+				pr.MergedAt = *issue.PullRequest.MergedAt
+			}
+			prs = append(prs, pr)
+		}
+	}
+
+	return prs, nil
+}
+
+func (giteaForge) PullRequestURL(repo Repository, number int) string {
+	return fmt.Sprintf("%s/%s/pulls/%d", strings.TrimSuffix(repo.BaseURL, "/"), repo.Name, number)
+}
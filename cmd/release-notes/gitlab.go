@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jespino/github-mm-release-notes/github"
+)
+
+// defaultGitLabURL is the GitLab instance of the repositories without a
+// base URL
+const defaultGitLabURL = "https://gitlab.com"
+
+// gitlabToken authenticates the requests to GitLab, from --gitlab-token or
+// the GITLAB_TOKEN environment variable
+var gitlabToken string
+
+// gitlabMilestone is a milestone of the GitLab API
+type gitlabMilestone struct {
+	IID         int       `json:"iid"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	DueDate     string    `json:"due_date"`
+}
+
+// gitlabMergeRequest is a merge request of the GitLab API
+type gitlabMergeRequest struct {
+	IID         int      `json:"iid"`
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Labels      []string `json:"labels"`
+	// State is opened, closed, locked or merged
+	State     string     `json:"state"`
+	MergedAt  *time.Time `json:"merged_at"`
+	CreatedAt time.Time  `json:"created_at"`
+	Author    struct {
+		Username string `json:"username"`
+	} `json:"author"`
+}
+
+// gitlabForge is the GitLab REST API, where PRs are merge requests and are
+// numbered by their iid
+type gitlabForge struct{}
+
+// gitlabAPIURL returns the URL of the REST API of the GitLab instance of a
+// repository
+func gitlabAPIURL(repo Repository) string {
+	baseURL := repo.BaseURL
+	if baseURL == "" {
+		baseURL = defaultGitLabURL
+	}
+	return strings.TrimSuffix(baseURL, "/") + "/api/v4"
+}
+
+func (gitlabForge) APIURL(repo Repository) string {
+	// Projects are identified by their URL-encoded path, e.g. group%2Fproject
+	return gitlabAPIURL(repo) + "/projects/" + url.PathEscape(repo.Name)
+}
+
+func (gitlabForge) Milestones(repo Repository) ([]Milestone, error) {
+	milestones, err := github.GetAllPages[gitlabMilestone](apiClient, repo.URL()+"/milestones?state=active")
+	if err != nil {
+		return nil, err
+	}
+
+	var result []Milestone
+	for _, m := range milestones {
+		milestone := Milestone{Number: m.IID, Title: m.Title, Description: m.Description, CreatedAt: m.CreatedAt}
+		if dueOn, err := time.Parse("2006-01-02", m.DueDate); err == nil {
+			milestone.DueOn = &dueOn
+		}
+		result = append(result, milestone)
+	}
+	return result, nil
+}
+
+func (gitlabForge) PullRequests(repo Repository, milestone Milestone) ([]PullRequest, error) {
+	// Merge requests are filtered by milestone title, and like on GitHub the
+	// labels filter matches the ones with all the labels
+	var prs []PullRequest
+	seen := make(map[int]bool)
+	for _, label := range releaseNoteLabels(repo.URL()) {
+		mrsURL := fmt.Sprintf("%s/merge_requests?state=all&milestone=%s&labels=%s", repo.URL(), url.QueryEscape(milestone.Title), url.QueryEscape(label))
+		mrs, err := github.GetAllPages[gitlabMergeRequest](apiClient, mrsURL)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, mr := range mrs {
+			if seen[mr.IID] {
+				continue
+			}
+			seen[mr.IID] = true
+
+			pr := PullRequest{Number: mr.IID, Title: mr.Title, Body: mr.Description, State: "closed", CreatedAt: mr.CreatedAt, RepoURL: repo.URL()}
+			if mr.State == "opened" {
+				pr.State = "open"
+			}
+			pr.User.Login = mr.Author.Username
+			pr.Milestone = &struct {
+				Number int `json:"number"`
+			}{Number: milestone.Number}
+			for _, name := range mr.Labels {
+				pr.Labels = append(pr.Labels, struct {
+					Name string `json:"name"`
+				}{Name: name})
+			}
+			if mr.MergedAt != nil {
+				pr.MergedAt = *mr.MergedAt
+			}
+			prs = append(prs, pr)
+		}
+	}
+
+	return prs, nil
+}
+
+func (gitlabForge) PullRequestURL(repo Repository, number int) string {
+	baseURL := repo.BaseURL
+	if baseURL == "" {
+		baseURL = defaultGitLabURL
+	}
+	return fmt.Sprintf("%s/%s/-/merge_requests/%d", strings.TrimSuffix(baseURL, "/"), repo.Name, number)
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// useGraphQL makes the milestones and PRs of the GitHub repositories be
+// fetched with the GraphQL API, which returns the PRs of a milestone with
+// their labels, authors and diff stats in a page of 100 for all the release
+// note labels, instead of a request per label and per PR with the REST API
+var useGraphQL bool
+
+// graphqlMilestonesQuery lists the open milestones of a repository
+const graphqlMilestonesQuery = `query($owner: String!, $name: String!, $cursor: String) {
+  repository(owner: $owner, name: $name) {
+    milestones(states: [OPEN], first: 100, after: $cursor) {
+      pageInfo { hasNextPage endCursor }
+      nodes { number title description createdAt dueOn }
+    }
+  }
+}`
+
+// graphqlPullRequestsQuery lists the PRs of a milestone with any of the
+// labels, along with the details fetched per PR with the REST API
+const graphqlPullRequestsQuery = `query($owner: String!, $name: String!, $number: Int!, $labels: [String!], $cursor: String) {
+  repository(owner: $owner, name: $name) {
+    milestone(number: $number) {
+      pullRequests(labels: $labels, first: 100, after: $cursor) {
+        pageInfo { hasNextPage endCursor }
+        nodes {
+          number title body state createdAt mergedAt additions deletions
+          author { login }
+          labels(first: 100) { nodes { name } }
+        }
+      }
+    }
+  }
+}`
+
+// graphqlPageInfo is the pagination of a GraphQL connection
+type graphqlPageInfo struct {
+	HasNextPage bool   `json:"hasNextPage"`
+	EndCursor   string `json:"endCursor"`
+}
+
+// graphqlPullRequest is a PR as returned by graphqlPullRequestsQuery
+type graphqlPullRequest struct {
+	Number    int        `json:"number"`
+	Title     string     `json:"title"`
+	Body      string     `json:"body"`
+	State     string     `json:"state"` // OPEN, CLOSED or MERGED
+	CreatedAt time.Time  `json:"createdAt"`
+	MergedAt  *time.Time `json:"mergedAt"`
+	Additions int        `json:"additions"`
+	Deletions int        `json:"deletions"`
+	Author    *struct {
+		Login string `json:"login"`
+	} `json:"author"`
+	Labels struct {
+		Nodes []struct {
+			Name string `json:"name"`
+		} `json:"nodes"`
+	} `json:"labels"`
+}
+
+// graphqlURL returns the URL of the GraphQL API, which is /api/graphql
+// rather than under the REST API on GitHub Enterprise Server
+func graphqlURL() string {
+	if apiBaseURL != "" && strings.HasSuffix(strings.TrimSuffix(apiBaseURL, "/"), "/api/v3") {
+		return strings.TrimSuffix(strings.TrimSuffix(apiBaseURL, "/"), "/v3") + "/graphql"
+	}
+	return githubAPIURL + "/graphql"
+}
+
+// queryGraphQL runs a GraphQL query and decodes its data into v, failing
+// with the errors reported in the response
+func queryGraphQL(query string, variables map[string]any, v any) error {
+	if authToken == "" {
+		return fmt.Errorf("the GitHub GraphQL API requires a token, set one with --token or the GITHUB_TOKEN environment variable")
+	}
+
+	var response struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := apiClient.DoJSON("POST", graphqlURL(), map[string]any{"query": query, "variables": variables}, &response); err != nil {
+		return err
+	}
+
+	if len(response.Errors) > 0 {
+		var messages []string
+		for _, e := range response.Errors {
+			messages = append(messages, e.Message)
+		}
+		return fmt.Errorf("GraphQL query failed: %s", strings.Join(messages, "; "))
+	}
+	return json.Unmarshal(response.Data, v)
+}
+
+// repoVariables returns the owner and name variables of a repository
+func repoVariables(repo Repository) map[string]any {
+	owner, name, _ := strings.Cut(repo.Name, "/")
+	return map[string]any{"owner": owner, "name": name}
+}
+
+// getMilestonesGraphQL gets all open milestones of the repository with the
+// GraphQL API
+func getMilestonesGraphQL(repo Repository) ([]Milestone, error) {
+	variables := repoVariables(repo)
+
+	var milestones []Milestone
+	for {
+		var data struct {
+			Repository struct {
+				Milestones struct {
+					PageInfo graphqlPageInfo `json:"pageInfo"`
+					Nodes    []struct {
+						Number      int        `json:"number"`
+						Title       string     `json:"title"`
+						Description string     `json:"description"`
+						CreatedAt   time.Time  `json:"createdAt"`
+						DueOn       *time.Time `json:"dueOn"`
+					} `json:"nodes"`
+				} `json:"milestones"`
+			} `json:"repository"`
+		}
+		if err := queryGraphQL(graphqlMilestonesQuery, variables, &data); err != nil {
+			return nil, err
+		}
+
+		for _, node := range data.Repository.Milestones.Nodes {
+			milestones = append(milestones, Milestone{
+				Number:      node.Number,
+				Title:       node.Title,
+				Description: node.Description,
+				CreatedAt:   node.CreatedAt,
+				DueOn:       node.DueOn,
+			})
+		}
+
+		pageInfo := data.Repository.Milestones.PageInfo
+		if !pageInfo.HasNextPage {
+			return milestones, nil
+		}
+		variables["cursor"] = pageInfo.EndCursor
+	}
+}
+
+// getPRsWithReleaseNotesGraphQL gets the PRs of a milestone with any of the
+// release note labels of the repository with the GraphQL API, along with
+// their diff stats and merge times
+func getPRsWithReleaseNotesGraphQL(repo Repository, milestone Milestone) ([]PullRequest, error) {
+	variables := repoVariables(repo)
+	variables["number"] = milestone.Number
+	variables["labels"] = releaseNoteLabels(repo.URL())
+
+	var prs []PullRequest
+	for {
+		var data struct {
+			Repository struct {
+				Milestone *struct {
+					PullRequests struct {
+						PageInfo graphqlPageInfo      `json:"pageInfo"`
+						Nodes    []graphqlPullRequest `json:"nodes"`
+					} `json:"pullRequests"`
+				} `json:"milestone"`
+			} `json:"repository"`
+		}
+		if err := queryGraphQL(graphqlPullRequestsQuery, variables, &data); err != nil {
+			return nil, err
+		}
+		if data.Repository.Milestone == nil {
+			return nil, fmt.Errorf("milestone %d not found in %s", milestone.Number, repo.Name)
+		}
+
+		for _, node := range data.Repository.Milestone.PullRequests.Nodes {
+			prs = append(prs, node.pullRequest(repo, milestone))
+		}
+
+		pageInfo := data.Repository.Milestone.PullRequests.PageInfo
+		if !pageInfo.HasNextPage {
+			return prs, nil
+		}
+		variables["cursor"] = pageInfo.EndCursor
+	}
+}
+
+// pullRequest converts the PR to the one listed by the REST API, with the
+// details already fetched
+func (node graphqlPullRequest) pullRequest(repo Repository, milestone Milestone) PullRequest {
+	pr := PullRequest{
+		Number:         node.Number,
+		Title:          node.Title,
+		Body:           node.Body,
+		State:          "closed",
+		CreatedAt:      node.CreatedAt,
+		RepoURL:        repo.URL(),
+		Additions:      node.Additions,
+		Deletions:      node.Deletions,
+		detailsFetched: true,
+	}
+	pr.Milestone = &struct {
+		Number int `json:"number"`
+	}{milestone.Number}
+	if node.State == "OPEN" {
+		pr.State = "open"
+	}
+	if node.Author != nil {
+		pr.User.Login = node.Author.Login
+	}
+	if node.MergedAt != nil {
+		pr.MergedAt = *node.MergedAt
+	}
+	for _, label := range node.Labels.Nodes {
+		pr.Labels = append(pr.Labels, struct {
+			Name string `json:"name"`
+		}{label.Name})
+	}
+	return pr
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"net/url"
+	"strings"
+
+	"github.com/jespino/github-mm-release-notes/extract"
+	"github.com/jespino/github-mm-release-notes/internal/format"
+)
+
+// formatHTML renders the notes as a self-contained HTML page
+const formatHTML = "html"
+
+// htmlStyle is the stylesheet of the HTML page, inlined so the page can be
+// embedded without any other file
+const htmlStyle = `body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; max-width: 860px; margin: 2em auto; padding: 0 1em; color: #1f2328; line-height: 1.5; }
+h1 { border-bottom: 1px solid #d0d7de; padding-bottom: .3em; }
+h2 { margin-top: 2em; border-bottom: 1px solid #d0d7de; padding-bottom: .2em; }
+nav ul { list-style: none; padding: 0; display: flex; flex-wrap: wrap; gap: .5em 1.5em; }
+ul.notes { padding-left: 0; list-style: none; }
+ul.notes li { display: flex; gap: .6em; align-items: flex-start; margin: .6em 0; }
+.avatar { width: 24px; height: 24px; border-radius: 50%; flex: none; margin-top: .1em; }
+.links { color: #59636e; white-space: nowrap; }
+.provisional { color: #9a6700; font-style: italic; }
+.contributors { display: flex; flex-wrap: wrap; gap: .8em; }
+.contributors a { display: flex; align-items: center; gap: .4em; text-decoration: none; }
+a { color: #0969da; }`
+
+// avatarURL returns the URL of the avatar of a PR author, only known for the
+// GitHub repositories
+func avatarURL(repoURL string, login string) string {
+	if login == "" || !isGitHubRepo(repoURL) {
+		return ""
+	}
+	return "https://github.com/" + url.PathEscape(login) + ".png?size=48"
+}
+
+// htmlPRLinks returns the links to a PR and its duplicates, prefixed with
+// the repository when withRepo is set
+func htmlPRLinks(pr PullRequest, withRepo bool) string {
+	var links []string
+	for _, linked := range append([]PullRequest{pr}, pr.Duplicates...) {
+		linkText := fmt.Sprintf("#%d", linked.Number)
+		if withRepo {
+			linkText = repoNameFromURL(linked.RepoURL) + linkText
+		}
+		links = append(links, fmt.Sprintf(`<a href="%s">%s</a>`, html.EscapeString(prURL(linked)), html.EscapeString(linkText)))
+	}
+	return strings.Join(links, ", ")
+}
+
+// renderHTML prints the release notes as a self-contained HTML page for the
+// internal release portals: a section per type of change, linked from the
+// navigation at the top, with each note linking to its PR next to the avatar
+// of its author, and the contributors with --contributors. PRs without a
+// release note are left out.
+func renderHTML(prs []PullRequest, milestoneTitle string) {
+	var withNotes []PullRequest
+	repos := make(map[string]bool)
+	for _, pr := range prs {
+		if !extract.HasReleaseNote(prReleaseNote(pr)) {
+			continue
+		}
+		repos[pr.RepoURL] = true
+		for _, duplicate := range pr.Duplicates {
+			repos[duplicate.RepoURL] = true
+		}
+		withNotes = append(withNotes, pr)
+	}
+
+	if guessed := countGuessedTypes(withNotes); guessed > 0 {
+		logger.Info("Type of change guessed, review the provisional entries", "guessed", guessed, "total", len(withNotes))
+	}
+	groups := groupPRsByType(withNotes)
+
+	title := html.EscapeString(milestoneTitle)
+	fmt.Println("<!DOCTYPE html>")
+	fmt.Println(`<html lang="en">`)
+	fmt.Println("<head>")
+	fmt.Println(`<meta charset="utf-8">`)
+	fmt.Println(`<meta name="viewport" content="width=device-width, initial-scale=1">`)
+	fmt.Printf("<title>Release notes %s</title>\n", title)
+	fmt.Printf("<style>\n%s\n</style>\n", htmlStyle)
+	fmt.Println("</head>")
+	fmt.Println("<body>")
+	fmt.Printf("<h1>Release notes %s</h1>\n", title)
+
+	if len(groups) == 0 {
+		fmt.Println("<p>No release notes found.</p>")
+	} else {
+		fmt.Println("<nav><ul>")
+		for _, group := range groups {
+			fmt.Printf(`<li><a href="#%s">%s</a> (%d)</li>`+"\n", format.Anchor(group.Type), html.EscapeString(group.Type), len(group.PRs))
+		}
+		fmt.Println("</ul></nav>")
+	}
+
+	for _, group := range groups {
+		fmt.Printf(`<h2 id="%s">%s</h2>`+"\n", format.Anchor(group.Type), html.EscapeString(group.Type))
+		fmt.Println(`<ul class="notes">`)
+		for _, pr := range group.PRs {
+			fmt.Print("<li>")
+			if avatar := avatarURL(pr.RepoURL, pr.User.Login); avatar != "" && !pr.Manual {
+				fmt.Printf(`<img class="avatar" src="%s" alt="@%s" title="@%s">`, html.EscapeString(avatar), html.EscapeString(pr.User.Login), html.EscapeString(pr.User.Login))
+			}
+			// Keep the lines of multi-line notes
+			note := strings.ReplaceAll(html.EscapeString(releaseNoteForPR(pr)), "\n", "<br>")
+			fmt.Printf("<span>%s", note)
+			if !pr.Manual {
+				// PR numbers are ambiguous when listing several repositories
+				fmt.Printf(` <span class="links">(%s)</span>`, htmlPRLinks(pr, len(repos) > 1))
+			}
+			if provisionalMarker(pr, false) != "" {
+				fmt.Print(` <span class="provisional">(provisional)</span>`)
+			}
+			fmt.Println("</span></li>")
+		}
+		fmt.Println("</ul>")
+	}
+
+	if showContributors {
+		if contributors := collectContributors(withNotes); len(contributors) > 0 {
+			fmt.Println(`<h2 id="contributors">Thanks to our contributors</h2>`)
+			fmt.Println(`<div class="contributors">`)
+			for _, contributor := range contributors {
+				login := html.EscapeString(contributor.Login)
+				avatar := avatarURL(firstRepoURL(contributor), contributor.Login)
+				if avatar == "" {
+					fmt.Printf("<span>@%s</span>\n", login)
+					continue
+				}
+				fmt.Printf(`<a href="https://github.com/%s"><img class="avatar" src="%s" alt="">@%s</a>`+"\n", url.PathEscape(contributor.Login), html.EscapeString(avatar), login)
+			}
+			fmt.Println("</div>")
+		}
+	}
+
+	fmt.Println("</body>")
+	fmt.Println("</html>")
+}
+
+// firstRepoURL returns one of the repositories a contributor authored PRs in
+func firstRepoURL(contributor Contributor) string {
+	for repoURL := range contributor.firstPRs {
+		return repoURL
+	}
+	return ""
+}
@@ -0,0 +1,356 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// extractorChangelog is recorded as the extractor of the entries imported
+// from a published changelog
+const extractorChangelog = "changelog"
+
+// changelogFile and changelogVersion are the Markdown changelog and the
+// version whose notes the import-changelog command imports
+var (
+	changelogFile    string
+	changelogVersion string
+)
+
+var (
+	// markdownHeadingRegexp matches a Markdown heading, capturing its level
+	// and text
+	markdownHeadingRegexp = regexp.MustCompile(`^\s{0,3}(#{1,6})\s+(.*?)\s*#*\s*$`)
+	// listItemRegexp matches an item of a bulleted or numbered list,
+	// capturing its indentation and text
+	listItemRegexp = regexp.MustCompile(`^(\s*)(?:[-*+]|\d+[.)])\s+(.*)$`)
+	// anyVersionRegexp matches a version in a heading, e.g. "Release v9.5"
+	anyVersionRegexp = regexp.MustCompile(`\bv?\d+\.\d+`)
+	// changelogPRRegexp matches the references to PRs in a changelog: a
+	// Markdown link or URL to the PR, an owner/repo#123 reference or a #123
+	// reference to the PRs of the repository given with --repo
+	changelogPRRegexp = regexp.MustCompile(`\[[^\]]*\]\(https://github\.com/([\w.-]+/[\w.-]+)/pull/(\d+)[^)]*\)|https://github\.com/([\w.-]+/[\w.-]+)/pull/(\d+)|\b([\w.-]+/[\w.-]+)#(\d+)\b|(?:^|[^\w/])#(\d+)\b`)
+	// changelogRefsRegexp and changelogBareRefsRegexp match the references
+	// the rendering links again, in parentheses, e.g. "([#123](...),
+	// mattermost/enterprise#45)", or links ending an item, the latter
+	// including the bare #123 ones
+	changelogRefsRegexp     = refsRegexp(changelogRefPattern)
+	changelogBareRefsRegexp = refsRegexp(changelogRefPattern + `|#\d+\b`)
+)
+
+const (
+	// changelogLinkPattern matches a link to a PR
+	changelogLinkPattern = `\[[^\]]*\]\(https://github\.com/[\w.-]+/[\w.-]+/pull/\d+[^)]*\)|https://github\.com/[\w.-]+/[\w.-]+/pull/\d+`
+	// changelogRefPattern matches a link or an owner/repo#123 reference to a
+	// PR
+	changelogRefPattern = changelogLinkPattern + `|\b[\w.-]+/[\w.-]+#\d+\b`
+)
+
+// ChangelogEntry is an item of a published changelog
+type ChangelogEntry struct {
+	Note string
+	// Section is the type of change of the heading the item is under, e.g.
+	// "Bug Fixes", empty when the heading isn't one
+	Section string
+	// PRs are the owner/repo#123 references of the item
+	PRs []string
+}
+
+// versionHeadingRegexp returns the regexp matching the headings of a
+// version, e.g. "v9.5" matches "Release v9.5" and "9.5.0" but not "v9.5.1"
+func versionHeadingRegexp(version string) *regexp.Regexp {
+	version = strings.TrimSuffix(strings.TrimPrefix(strings.ToLower(version), "v"), ".0")
+	return regexp.MustCompile(`(?i)(^|[^\w.])v?` + regexp.QuoteMeta(version) + `(\.0)?($|[^\w.]|\.\D)`)
+}
+
+// changelogSection returns the lines of the section of the version, from its
+// heading to the next heading of the same or upper level. A changelog
+// without version headings is a single version.
+func changelogSection(lines []string, version string) ([]string, error) {
+	versionRegexp := versionHeadingRegexp(version)
+	hasVersions := false
+	for i, line := range lines {
+		matches := markdownHeadingRegexp.FindStringSubmatch(line)
+		if matches == nil {
+			continue
+		}
+		if !versionRegexp.MatchString(matches[2]) {
+			hasVersions = hasVersions || anyVersionRegexp.MatchString(matches[2])
+			continue
+		}
+
+		level := len(matches[1])
+		end := len(lines)
+		for j := i + 1; j < len(lines); j++ {
+			if next := markdownHeadingRegexp.FindStringSubmatch(lines[j]); next != nil && len(next[1]) <= level {
+				end = j
+				break
+			}
+		}
+		return lines[i+1 : end], nil
+	}
+
+	if hasVersions {
+		return nil, fmt.Errorf("no heading of version %s found in the changelog", version)
+	}
+	return lines, nil
+}
+
+// skippedChangelogSections are the words of the headings of the changelog
+// sections that don't list changes, left out of the import
+var skippedChangelogSections = []string{"known issue", "contributor"}
+
+// parseChangelog returns the items of the lists of a Markdown changelog
+// section. It is tolerant of the hand-written formats: nested items and
+// indented lines are kept in the item they belong to, and text outside the
+// lists is ignored, like the known issues and contributors sections. Bare
+// #123 references are resolved to defaultRepo, when given.
+func parseChangelog(lines []string, defaultRepo string) []ChangelogEntry {
+	var entries []ChangelogEntry
+	var current []string
+	section, sectionLevel := "", 0
+	skipLevel := 0
+	blank := false
+
+	flush := func() {
+		if current != nil {
+			note, prs := changelogNote(strings.Join(current, "\n"), defaultRepo)
+			if note != "" {
+				entries = append(entries, ChangelogEntry{Note: note, Section: section, PRs: prs})
+			}
+		}
+		current = nil
+	}
+
+	for _, line := range lines {
+		if matches := markdownHeadingRegexp.FindStringSubmatch(line); matches != nil {
+			flush()
+			level := len(matches[1])
+			if skipLevel > 0 && level > skipLevel {
+				continue
+			}
+			skipLevel = 0
+			for _, word := range skippedChangelogSections {
+				if strings.Contains(strings.ToLower(matches[2]), word) {
+					skipLevel = level
+				}
+			}
+
+			// Headings below a type of change, e.g. "User Interface" under
+			// "Improvements", keep it
+			if changeType := changelogSectionType(matches[2]); changeType != "" {
+				section, sectionLevel = changeType, level
+			} else if level <= sectionLevel {
+				section, sectionLevel = "", 0
+			}
+			continue
+		}
+		if skipLevel > 0 {
+			continue
+		}
+		if strings.TrimSpace(line) == "" {
+			blank = true
+			continue
+		}
+
+		indented := strings.HasPrefix(line, "  ") || strings.HasPrefix(line, "\t")
+		if matches := listItemRegexp.FindStringSubmatch(line); matches != nil && len(matches[1]) < 2 {
+			flush()
+			current = []string{matches[2]}
+		} else if current != nil && (indented || !blank) {
+			// Nested items and continuation lines of the item
+			if listItemRegexp.MatchString(line) {
+				current = append(current, "  "+strings.TrimSpace(line))
+			} else {
+				current[len(current)-1] += " " + strings.TrimSpace(line)
+			}
+		} else {
+			flush()
+		}
+		blank = false
+	}
+	flush()
+
+	return entries
+}
+
+// changelogSectionTypes are the words of the headings of each type of change
+var changelogSectionTypes = []struct {
+	word       string
+	changeType string
+}{
+	{"breaking", typeBreaking},
+	{"deprecat", typeDeprecation},
+	{"feature", typeFeature},
+	{"improvement", typeImprovement},
+	{"fix", typeBugFix},
+}
+
+// changelogSectionType returns the type of change of a heading, e.g. "Bug
+// Fixes" for "Bug fixes", or an empty string when it isn't one
+func changelogSectionType(heading string) string {
+	heading = strings.ToLower(heading)
+	for _, section := range changelogSectionTypes {
+		if strings.Contains(heading, section.word) {
+			return section.changeType
+		}
+	}
+	return ""
+}
+
+// refsRegexp returns the regexp matching a list of references in
+// parentheses, or of links ending the text. The references ending the text
+// are part of it, e.g. "Fixed a regression of mattermost/enterprise#45".
+func refsRegexp(ref string) *regexp.Regexp {
+	list := `(?:` + ref + `)(?:\s*[,;]\s*(?:` + ref + `))*`
+	links := `(?:` + changelogLinkPattern + `)(?:\s*[,;]\s*(?:` + changelogLinkPattern + `))*`
+	return regexp.MustCompile(`[ \t]*\(\s*` + list + `\s*\)|[ \t]+` + links + `\s*$`)
+}
+
+// changelogNote returns the text of an item without the references to PRs
+// in parentheses or ending it, which the rendering links again, and the PRs
+// it references in the owner/repo#123 form
+func changelogNote(text string, defaultRepo string) (string, []string) {
+	var prs []string
+	for _, groups := range changelogPRRegexp.FindAllStringSubmatch(text, -1) {
+		var ref string
+		switch {
+		case groups[1] != "":
+			ref = groups[1] + "#" + groups[2]
+		case groups[3] != "":
+			ref = groups[3] + "#" + groups[4]
+		case groups[5] != "":
+			ref = groups[5] + "#" + groups[6]
+		case defaultRepo != "":
+			ref = defaultRepo + "#" + groups[7]
+		default:
+			// Bare references can't be resolved without a repository
+			continue
+		}
+		if !containsString(prs, ref) {
+			prs = append(prs, ref)
+		}
+	}
+
+	refs := changelogRefsRegexp
+	if defaultRepo != "" {
+		refs = changelogBareRefsRegexp
+	}
+	note := refs.ReplaceAllString(text, "")
+	note = strings.TrimSpace(note)
+	return note, prs
+}
+
+// changelogCategories returns the labels selecting the type of change of a
+// section of the changelog, as configured for the PRs
+func changelogCategories(section string) []string {
+	var labels []string
+	switch section {
+	case typeBreaking:
+		labels = config.BreakingLabels
+	case typeDeprecation:
+		labels = config.DeprecationLabels
+	case typeFeature:
+		labels = splitList(featureLabels)
+	case typeBugFix:
+		labels = config.BugLabels
+	}
+	if len(labels) == 0 {
+		return nil
+	}
+	return labels[:1]
+}
+
+// buildChangelogReleaseSet returns the release set of the entries imported
+// from a changelog. The entries referencing PRs are linked to the first one,
+// the rest being recorded as duplicates, and the ones without a PR are
+// imported as manual entries of the repository.
+func buildChangelogReleaseSet(entries []ChangelogEntry, version string, repoName string, defaultRepo string) ReleaseSet {
+	set := ReleaseSet{Milestone: version, Title: milestoneHeader(version), RepoName: repoName, GeneratedAt: time.Now().UTC()}
+	for _, entry := range entries {
+		title, _, _ := strings.Cut(entry.Note, "\n")
+		releaseEntry := ReleaseEntry{
+			Repo:       defaultRepo,
+			Title:      title,
+			Note:       entry.Note,
+			Categories: changelogCategories(entry.Section),
+			Provenance: &Provenance{Repo: defaultRepo, Extractor: extractorManual, ExtractedAt: set.GeneratedAt},
+		}
+		if len(entry.PRs) > 0 {
+			repo, number, _ := parsePRRef(entry.PRs[0])
+			releaseEntry.Repo = repo
+			releaseEntry.Number = number
+			releaseEntry.Duplicates = entry.PRs[1:]
+			releaseEntry.Provenance = &Provenance{Repo: repo, PR: number, Extractor: extractorChangelog, ExtractedAt: set.GeneratedAt}
+		}
+		set.Entries = append(set.Entries, releaseEntry)
+	}
+	return set
+}
+
+// runImportChangelog implements the import-changelog command, which imports
+// the notes of a version of a hand-written Markdown changelog (--file and
+// --version) into a release set, for the releases before the tool. It is
+// written to --release-set, or along the backfilled ones in --backfill-dir.
+func runImportChangelog() error {
+	if changelogFile == "" || changelogVersion == "" {
+		return fmt.Errorf("the --file flag with the changelog and the --version flag with the version to import are required")
+	}
+
+	repoName := "all repositories"
+	defaultRepo := repoNameFromURL(allRepoURLs[0])
+	bareRefsRepo := ""
+	if repoFlag != "" {
+		option, ok := findRepoOption(repoOptions(), repoFlag)
+		if !ok {
+			return fmt.Errorf("invalid repository %q", repoFlag)
+		}
+		repoName = option.Name
+		defaultRepo = option.Repos[0].Name
+		if len(option.Repos) == 1 {
+			bareRefsRepo = defaultRepo
+		}
+	}
+
+	path := releaseSetFile
+	if path == "" {
+		if err := os.MkdirAll(backfillDir, 0755); err != nil {
+			return err
+		}
+		path = backfillPath(changelogVersion)
+	}
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("%s already exists, delete it to import the changelog again", path)
+	}
+
+	data, err := os.ReadFile(changelogFile)
+	if err != nil {
+		return err
+	}
+	lines, err := changelogSection(strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n"), changelogVersion)
+	if err != nil {
+		return fmt.Errorf("error importing %s: %w", filepath.Base(changelogFile), err)
+	}
+
+	entries := parseChangelog(lines, bareRefsRepo)
+	if len(entries) == 0 {
+		return fmt.Errorf("no entries of version %s found in %s", changelogVersion, changelogFile)
+	}
+
+	set := buildChangelogReleaseSet(entries, changelogVersion, repoName, defaultRepo)
+	if err := writeReleaseSet(path, set); err != nil {
+		return err
+	}
+
+	withPRs := 0
+	for _, entry := range set.Entries {
+		if entry.Number != 0 {
+			withPRs++
+		}
+	}
+	fmt.Printf("Imported %d entries of %s into %s, %d without a PR as manual entries\n", len(set.Entries), changelogVersion, path, len(set.Entries)-withPRs)
+	return nil
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/jespino/github-mm-release-notes/github"
+)
+
+// Issue is a GitHub issue
+type Issue struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	Labels []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+	Milestone *struct {
+		Title string `json:"title"`
+	} `json:"milestone"`
+	PullRequest *struct{} `json:"pull_request"` // Set when the issue is a PR
+	RepoURL     string    `json:"-"`            // Internal field, not from API
+}
+
+// getOpenIssues returns the open issues (not PRs) of a repository with the
+// given label, restricted to a milestone when milestoneID is not zero
+func getOpenIssues(repoURL string, milestoneID int, label string) ([]Issue, error) {
+	query := url.Values{}
+	query.Set("state", "open")
+	query.Set("labels", label)
+	if milestoneID != 0 {
+		query.Set("milestone", fmt.Sprint(milestoneID))
+	}
+
+	issues, err := github.GetAllPages[Issue](apiClient, fmt.Sprintf("%s/issues?%s", repoURL, query.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	var result []Issue
+	for _, issue := range issues {
+		if issue.PullRequest == nil {
+			issue.RepoURL = repoURL
+			result = append(result, issue)
+		}
+	}
+
+	return result, nil
+}
+
+// getKnownIssues returns the open bugs still assigned to the milestones and
+// the open issues labeled as known issues in their repositories
+func getKnownIssues(milestones []Milestone) ([]Issue, error) {
+	var issues []Issue
+	seen := make(map[string]bool)
+	add := func(found []Issue) {
+		for _, issue := range found {
+			key := fmt.Sprintf("%s#%d", issue.RepoURL, issue.Number)
+			if !seen[key] {
+				seen[key] = true
+				issues = append(issues, issue)
+			}
+		}
+	}
+
+	var repos []string
+	for _, milestone := range milestones {
+		for _, label := range config.BugLabels {
+			found, err := getOpenIssues(milestone.RepoURL, milestone.Number, label)
+			if err != nil {
+				return nil, err
+			}
+			add(found)
+		}
+
+		if !containsString(repos, milestone.RepoURL) {
+			repos = append(repos, milestone.RepoURL)
+		}
+	}
+
+	for _, repoURL := range repos {
+		for _, label := range config.KnownIssueLabels {
+			found, err := getOpenIssues(repoURL, 0, label)
+			if err != nil {
+				return nil, err
+			}
+			add(found)
+		}
+	}
+
+	return issues, nil
+}
+
+// printKnownIssues prints the "Known issues" section
+func printKnownIssues(issues []Issue) {
+	if len(issues) == 0 {
+		return
+	}
+
+	fmt.Println("Known issues:")
+	fmt.Println()
+	for _, issue := range issues {
+		fmt.Printf("- %s (%s#%d)\n", issue.Title, repoNameFromURL(issue.RepoURL), issue.Number)
+	}
+	fmt.Println()
+}
@@ -0,0 +1,35 @@
+package main
+
+// requiredLabels and excludedLabels narrow the PRs with release notes to the
+// ones having all the --label labels and none of the --exclude-label ones,
+// e.g. only area/plugins, or without do-not-merge
+var (
+	requiredLabels stringList
+	excludedLabels stringList
+)
+
+// filterPRsByLabels keeps the PRs with all the required labels and none of
+// the excluded ones, compared ignoring case
+func filterPRsByLabels(prs []PullRequest, required []string, excluded []string) []PullRequest {
+	if len(required) == 0 && len(excluded) == 0 {
+		return prs
+	}
+
+	var result []PullRequest
+	for _, pr := range prs {
+		if hasAnyLabel(pr, excluded) {
+			continue
+		}
+		missing := false
+		for _, label := range required {
+			if !hasAnyLabel(pr, []string{label}) {
+				missing = true
+				break
+			}
+		}
+		if !missing {
+			result = append(result, pr)
+		}
+	}
+	return result
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// latestMilestoneTitle returns the title of the milestone a scheduled run
+// should work on: the next upcoming one by due date or, when all are past
+// due, the most recently due. Milestones without a due date are ignored.
+func latestMilestoneTitle(milestones []Milestone, now time.Time) (string, bool) {
+	var upcoming, past *Milestone
+	for i := range milestones {
+		milestone := &milestones[i]
+		if milestone.DueOn == nil {
+			continue
+		}
+
+		if !milestone.DueOn.Before(now) {
+			if upcoming == nil || milestone.DueOn.Before(*upcoming.DueOn) {
+				upcoming = milestone
+			}
+		} else if past == nil || milestone.DueOn.After(*past.DueOn) {
+			past = milestone
+		}
+	}
+
+	switch {
+	case upcoming != nil:
+		return upcoming.Title, true
+	case past != nil:
+		return past.Title, true
+	default:
+		return "", false
+	}
+}
+
+// selectLatestMilestone sets the --milestone flag to the latest milestone of
+// the repositories when --latest is given
+func selectLatestMilestone(milestoneSets [][]Milestone) error {
+	if !latestMilestone {
+		return nil
+	}
+
+	var milestones []Milestone
+	for _, set := range milestoneSets {
+		milestones = append(milestones, set...)
+	}
+
+	title, ok := latestMilestoneTitle(milestones, time.Now())
+	if !ok {
+		return fmt.Errorf("no open milestone with a due date found for --latest")
+	}
+	milestoneFlag = title
+	logger.Info("Selected the latest milestone by due date", "milestone", title)
+
+	return nil
+}
+
+// resolveLatestMilestone selects the latest milestone of the repositories
+// of the --repo flag, or of all of them, for the commands when --latest is
+// given
+func resolveLatestMilestone() error {
+	if !latestMilestone {
+		return nil
+	}
+
+	repos := config.Repositories
+	if repoFlag != "" {
+		option, ok := findRepoOption(repoOptions(), repoFlag)
+		if !ok {
+			return fmt.Errorf("invalid repository %q", repoFlag)
+		}
+		repos = option.Repos
+	}
+
+	milestoneSets, err := fetchMilestones(repos)
+	if err != nil {
+		return err
+	}
+	return selectLatestMilestone(milestoneSets)
+}
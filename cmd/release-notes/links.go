@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+// githubLinkRegexp matches links to GitHub PRs and issues
+var githubLinkRegexp = regexp.MustCompile(`https?://github\.com/([\w.-]+/[\w.-]+)/(?:pull|issues)/(\d+)(?:/[\w-]*)?(?:[?#][^\s)\]]*)?`)
+
+// LinkShortener shortens the links to GitHub PRs and issues in the notes
+// posted to chat targets, through a URL template such as
+// "https://go.example.com/{{.Repo}}/{{.Number}}"
+type LinkShortener struct {
+	URLTemplate string `yaml:"url_template"`
+
+	tmpl *template.Template
+}
+
+// LinkShortenerData holds the placeholders available in the URL template of
+// the link shortener
+type LinkShortenerData struct {
+	URL    string
+	Repo   string
+	Number int
+}
+
+// compile parses the URL template of the link shortener, if any
+func (s *LinkShortener) compile() error {
+	if s.URLTemplate == "" {
+		return nil
+	}
+	tmpl, err := template.New("chat_link_shortener").Parse(s.URLTemplate)
+	if err != nil {
+		return err
+	}
+	s.tmpl = tmpl
+	return nil
+}
+
+// shorten returns the short link of a GitHub link, or the link as it is if
+// the template fails
+func (s LinkShortener) shorten(link string) string {
+	groups := githubLinkRegexp.FindStringSubmatch(link)
+	number, _ := strconv.Atoi(groups[2])
+
+	var buf strings.Builder
+	if err := s.tmpl.Execute(&buf, LinkShortenerData{URL: link, Repo: groups[1], Number: number}); err != nil {
+		logger.Warn("Error applying the chat link shortener, keeping the link", "link", link, "error", err)
+		return link
+	}
+	return buf.String()
+}
+
+// shortenLinks compresses the links to GitHub PRs and issues in text to the
+// owner/repo#123 form, which GitHub renders as an autolink. Chat targets
+// don't link that form, so the notes posted to Mattermost go through the
+// chat link shortener of the configuration instead, when there is one.
+func shortenLinks(text string) string {
+	if postToMattermost() && config.ChatLinkShortener.tmpl != nil {
+		return githubLinkRegexp.ReplaceAllStringFunc(text, config.ChatLinkShortener.shorten)
+	}
+	return githubLinkRegexp.ReplaceAllString(text, "$1#$2")
+}
+
+// prRefRegexp matches references to PRs and issues of other repositories in
+// the owner/repo#123 form, not inside URLs or Markdown links
+var prRefRegexp = regexp.MustCompile(`(^|[^\w/\[.-])([\w.-]+/[\w.-]+)#(\d+)\b`)
+
+// refTitleCache caches the titles of the referenced PRs and issues, shared by
+// the milestones processed in parallel
+var (
+	refTitleMu    sync.Mutex
+	refTitleCache = make(map[string]string)
+)
+
+// refTitle returns the title of a referenced PR or issue, or an empty string
+// if it can't be fetched
+func refTitle(repo string, number string) string {
+	key := repo + "#" + number
+	refTitleMu.Lock()
+	title, ok := refTitleCache[key]
+	refTitleMu.Unlock()
+	if ok {
+		return title
+	}
+
+	var issue struct {
+		Title string `json:"title"`
+	}
+	if err := apiClient.GetJSON(fmt.Sprintf("%s/issues/%s", repoURLFromName(repo), number), &issue); err != nil {
+		logger.Warn("Can't get the title of a referenced PR", "ref", key, "error", err)
+	}
+	refTitleMu.Lock()
+	refTitleCache[key] = issue.Title
+	refTitleMu.Unlock()
+
+	return issue.Title
+}
+
+// resolveRefs turns the owner/repo#123 references in text into full links,
+// so notes referencing other repositories read correctly outside GitHub,
+// with the title of the referenced PR or issue when --ref-titles is given
+func resolveRefs(text string, markdown bool) string {
+	return prRefRegexp.ReplaceAllStringFunc(text, func(match string) string {
+		groups := prRefRegexp.FindStringSubmatch(match)
+		prefix, repo, number := groups[1], groups[2], groups[3]
+		ref := repo + "#" + number
+		url := fmt.Sprintf("https://github.com/%s/pull/%s", repo, number)
+
+		title := ""
+		if inlineRefTitles {
+			title = refTitle(repo, number)
+		}
+
+		switch {
+		case markdown && title != "":
+			return fmt.Sprintf("%s[%s %s](%s)", prefix, ref, title, url)
+		case markdown:
+			return fmt.Sprintf("%s[%s](%s)", prefix, ref, url)
+		case title != "":
+			return fmt.Sprintf("%s%s %q (%s)", prefix, ref, title, url)
+		default:
+			return fmt.Sprintf("%s%s (%s)", prefix, ref, url)
+		}
+	})
+}
@@ -0,0 +1,14 @@
+package main
+
+import (
+	"os"
+
+	"github.com/jespino/github-mm-release-notes/github"
+)
+
+// consoleLogger writes the diagnostic messages to stderr, keeping them out of
+// the notes printed to stdout. Debug messages are enabled with --verbose.
+var consoleLogger = &github.ConsoleLogger{Out: os.Stderr}
+
+// logger is used for every diagnostic message of the tool
+var logger github.Logger = consoleLogger
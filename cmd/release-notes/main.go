@@ -0,0 +1,330 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jespino/github-mm-release-notes/github"
+)
+
+// Mattermost Release Notes Extractor
+//
+// This tool helps extract release notes from GitHub pull requests in Mattermost repositories.
+// It retrieves PRs with the "release-note" label from selected milestones and displays their release notes.
+//
+// Usage:
+//   ./release-notes-extractor [--token=YOUR_GITHUB_TOKEN]
+//
+// Token can be provided in three ways (in order of precedence):
+//   1. Command line flag: --token=YOUR_TOKEN
+//   2. Environment variable: export GITHUB_TOKEN=YOUR_TOKEN
+//   3. Default token defined in the code (not recommended)
+
+// URL of the GitHub REST API
+const githubAPIURL = github.APIURL
+
+// apiBaseURL replaces githubAPIURL in the requests when set with --api-url or
+// the GITHUB_API_URL environment variable, e.g. to run against fakegithub
+var apiBaseURL string
+
+const (
+	// mattermostRepoURL is the monorepo, which some options like --areas
+	// and --settings handle specially
+	mattermostRepoURL = "https://api.github.com/repos/mattermost/mattermost"
+	defaultAuthToken  = "" // Default token, lowest priority
+)
+
+// allRepoURLs lists every repository the tool works with, as configured
+var allRepoURLs = repositoryURLs(defaultRepositories)
+
+var authToken string
+
+// max returns the larger of x or y
+func max(x, y int) int {
+	if x > y {
+		return x
+	}
+	return y
+}
+
+func main() {
+	defer recoverWithDiagnostics()
+
+	// Commands are given as the first argument, before the flags
+	command := ""
+	if len(os.Args) > 1 && !strings.HasPrefix(os.Args[1], "-") {
+		command = os.Args[1]
+		os.Args = append(os.Args[:1], os.Args[2:]...)
+
+		// Some commands have subcommands, e.g. "bundle export"
+		if command == "bundle" && len(os.Args) > 1 && !strings.HasPrefix(os.Args[1], "-") {
+			command += " " + os.Args[1]
+			os.Args = append(os.Args[:1], os.Args[2:]...)
+		}
+	}
+
+	// Get GitHub token from available sources
+	authToken = getGitHubToken()
+
+	if authToken == "" {
+		logger.Warn("No GitHub token found. Access to private repositories will fail.")
+	} else {
+		tokenLength := len(authToken)
+		logger.Info(fmt.Sprintf("Using GitHub token (last 4 chars: %s)", authToken[max(0, tokenLength-4):tokenLength]))
+	}
+
+	var err error
+	if config, err = loadConfig(configFile); err != nil {
+		logger.Error("Error loading config", "error", err)
+		os.Exit(1)
+	}
+	allRepoURLs = repositoryURLs(config.Repositories)
+	apiClient = newAPIClient()
+
+	if skuFilter != "" {
+		if _, ok := findSKU(skuFilter); !ok {
+			fmt.Printf("Invalid SKU %q, must be one of: %s\n", skuFilter, strings.Join(skuNames(), ", "))
+			os.Exit(1)
+		}
+	}
+
+	// A group is selected like the other options of the repository menu
+	if repoGroup != "" {
+		if repoFlag != "" {
+			fmt.Println("The --repo and --group flags can't be used together")
+			os.Exit(1)
+		}
+		if _, ok := config.RepoGroups[repoGroup]; !ok {
+			fmt.Printf("Unknown repository group %q, define it in the repo_groups of the config file\n", repoGroup)
+			os.Exit(1)
+		}
+		repoFlag = repoGroup
+	}
+
+	if reviewCutoff != "" {
+		if reviewCutoffTime, err = time.Parse("2006-01-02", reviewCutoff); err != nil {
+			fmt.Printf("Invalid review cutoff %q, must be a YYYY-MM-DD date\n", reviewCutoff)
+			os.Exit(1)
+		}
+	}
+
+	if !containsString(outputFormats, outputFormat) {
+		fmt.Printf("Invalid format %q, must be one of: %s\n", outputFormat, strings.Join(outputFormats, ", "))
+		os.Exit(1)
+	}
+	if shortLinks && resolveRefLinks {
+		fmt.Println("The --short-links and --resolve-refs flags can't be used together")
+		os.Exit(1)
+	}
+	if useClaudeFormat && outputFormat != formatText {
+		fmt.Println("The --claude flag can only be used with the text format")
+		os.Exit(1)
+	}
+	if templateFile != "" && (useClaudeFormat || outputFormat != formatText) {
+		fmt.Println("The --template flag can't be used with --claude or --format")
+		os.Exit(1)
+	}
+
+	if markProvisional && (publishReleaseTag != "" || postToMattermost()) {
+		fmt.Println("The --mark-provisional flag can't be used with --publish-release or posting to Mattermost, which publish the notes")
+		os.Exit(1)
+	}
+
+	if splitDelivery && (useClaudeFormat || templateFile != "" || publishReleaseTag != "" || postToMattermost()) {
+		fmt.Println("The --split-delivery flag can't be used with --claude, --template, --publish-release or posting to Mattermost")
+		os.Exit(1)
+	}
+
+	if len(milestoneFlags) == 1 && !multipleMilestones() {
+		milestoneFlag = milestoneFlags[0]
+	}
+	if outputFormat == formatHTML {
+		if multipleMilestones() {
+			fmt.Println("The html format takes a single --milestone")
+			os.Exit(1)
+		}
+		if showSettings || showDevSections || showPerformance || showA11y || showFlags || showKnownIssues || showCarryover || firstTimeContributors {
+			fmt.Println("The html format only supports the --contributors section")
+			os.Exit(1)
+		}
+	}
+	if splitDelivery && multipleMilestones() {
+		fmt.Println("The --split-delivery flag takes a single --milestone")
+		os.Exit(1)
+	}
+	if latestMilestone && len(milestoneFlags) > 0 {
+		fmt.Println("The --latest and --milestone flags can't be used together")
+		os.Exit(1)
+	}
+
+	if showRateLimit {
+		defer printRateLimit()
+	}
+
+	if command != "" {
+		if multipleMilestones() {
+			logger.Error("Error", "error", fmt.Errorf("the %s command takes a single --milestone", command))
+			os.Exit(1)
+		}
+		if err := resolveLatestMilestone(); err != nil {
+			logger.Error("Error", "error", err)
+			os.Exit(1)
+		}
+		if err := runCommand(command); err != nil {
+			logger.Error("Error", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// The anonymized notes are for sharing, not for storing
+	if anonymizeOutput && (releaseSetFile != "" || rcNumber > 0) {
+		fmt.Println("The --anonymize flag can't be used with --release-set or --rc, which store the notes")
+		os.Exit(1)
+	}
+	if anonymizeOutput && (showContributors || firstTimeContributors) {
+		fmt.Println("The --anonymize flag can't be used with --contributors, which lists the author handles")
+		os.Exit(1)
+	}
+
+	if useGraphQL && authToken == "" {
+		fmt.Println("The --graphql flag requires a GitHub token, the GraphQL API doesn't allow anonymous requests")
+		os.Exit(1)
+	}
+
+	if err := checkMattermostFlags(); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	// Select repository, from the flag when running non-interactively
+	options := repoOptions()
+	var selectedRepos RepoOption
+	if repoFlag != "" {
+		var ok bool
+		if selectedRepos, ok = findRepoOption(options, repoFlag); !ok {
+			fmt.Printf("Invalid repository %q, must be one of: %s\n", repoFlag, strings.Join(repoFlagValues(options), ", "))
+			os.Exit(1)
+		}
+	} else {
+		fmt.Println("Select a repository:")
+		for i, choice := range options {
+			fmt.Printf("%d: %s\n", i+1, choice.Name)
+		}
+
+		reader := bufio.NewReader(os.Stdin)
+		fmt.Printf("\nSelect an option (1-%d): ", len(options))
+		repoInput, _ := reader.ReadString('\n')
+		repoInput = strings.TrimSpace(repoInput)
+
+		repoChoice, err := strconv.Atoi(repoInput)
+		if err != nil || repoChoice < 1 || repoChoice > len(options) {
+			fmt.Println("Invalid selection")
+			os.Exit(1)
+		}
+		selectedRepos = options[repoChoice-1]
+	}
+
+	repoName := selectedRepos.Name
+	if len(selectedRepos.Repos) == 1 {
+		repoName = selectedRepos.Repos[0].Name
+	}
+
+	// Get the milestones of every repository of the option, unified by name
+	// when there are several
+	milestoneSets, err := fetchMilestones(selectedRepos.Repos)
+	if err != nil {
+		logger.Error("Error getting milestones", "error", err)
+		os.Exit(1)
+	}
+	if err := selectLatestMilestone(milestoneSets); err != nil {
+		logger.Error("Error", "error", err)
+		os.Exit(1)
+	}
+
+	var milestones []Milestone
+	var unifiedMilestones []UnifiedMilestone
+	if len(milestoneSets) == 1 {
+		milestones = milestoneSets[0]
+	} else {
+		unifiedMilestones = unifyMilestonesByName(milestoneSets...)
+
+		// Convert back to simple milestones for display and selection
+		for _, um := range unifiedMilestones {
+			// Use the first milestone as the representative for this name
+			milestones = append(milestones, um.Milestones[0])
+		}
+	}
+
+	fmt.Printf("\nWorking with %s\n", repoName)
+
+	// Several milestones, from --milestone given several times or as a glob
+	if multipleMilestones() {
+		selected, err := matchMilestones(milestones, milestoneFlags)
+		if err == nil {
+			err = generateMultipleReleaseNotes(selected, unifiedMilestones, repoName)
+		}
+		if err != nil {
+			logger.Error("Error", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	var selectedMilestone Milestone
+	if milestoneFlag != "" {
+		// Select the milestone by title when running non-interactively
+		found := false
+		for _, milestone := range milestones {
+			if milestone.Title == milestoneFlag {
+				selectedMilestone = milestone
+				found = true
+				break
+			}
+		}
+		if !found {
+			fmt.Printf("No open milestone %q found in %s\n", milestoneFlag, repoName)
+			os.Exit(1)
+		}
+	} else {
+		// Allow user to select a milestone, by number or filtering by title
+		var ok bool
+		if selectedMilestone, ok = pickMilestone(bufio.NewReader(os.Stdin), milestones); !ok {
+			fmt.Println("Invalid selection")
+			os.Exit(1)
+		}
+	}
+	fmt.Printf("\nSelected milestone: %s\n\n", selectedMilestone.Title)
+
+	// Milestones matching the selection in each repository
+	targetMilestones := milestoneTargets(selectedMilestone, unifiedMilestones)
+
+	if checkMissing {
+		if err := checkMissingNotes(githubMilestones(targetMilestones, "--check-missing")); err != nil {
+			logger.Error("Error", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Get PRs with release notes for each matching milestone
+	prs, errs := fetchPRs(targetMilestones)
+	for i, err := range errs {
+		if err == nil {
+			continue
+		}
+		logger.Error("Error getting PRs", "repo", repoNameFromURL(targetMilestones[i].RepoURL), "error", err)
+		if len(targetMilestones) == 1 {
+			os.Exit(1)
+		}
+	}
+
+	if err := generateReleaseNotes(prs, selectedMilestone.Title, targetMilestones, repoName); err != nil {
+		logger.Error("Error", "error", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// extractorManual is recorded as the extractor of hand-written entries
+const extractorManual = "manual"
+
+// ManualEntry is a hand-written entry, e.g. for an infrastructure change
+// with no PR, merged into the generated notes with --manual-entries
+type ManualEntry struct {
+	// Repo is the owner/repo the entry belongs to, which selects its area,
+	// defaults to the first configured repository
+	Repo string `yaml:"repo"`
+	// Title describes the entry for the editors, defaults to the note
+	Title string `yaml:"title"`
+	Note  string `yaml:"note"`
+	// Categories are labels, which select the sections the entry is listed
+	// in like the labels of a PR
+	Categories []string `yaml:"categories"`
+	// Author is who wrote the entry, recorded in its provenance
+	Author string `yaml:"author"`
+}
+
+// loadManualEntries reads a manual entries file and returns its entries as
+// PRs flowing through the same rendering pipeline as the extracted ones
+func loadManualEntries(path string) ([]PullRequest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file struct {
+		Entries []ManualEntry `yaml:"entries"`
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid manual entries file %s: %w", path, err)
+	}
+
+	var prs []PullRequest
+	for i, entry := range file.Entries {
+		if strings.TrimSpace(entry.Note) == "" {
+			return nil, fmt.Errorf("invalid manual entries file %s: entry %d has no note", path, i+1)
+		}
+
+		repoURL := allRepoURLs[0]
+		if entry.Repo != "" {
+			repoURL = repoURLFromName(entry.Repo)
+		}
+		title := entry.Title
+		if title == "" {
+			title = entry.Note
+		}
+
+		pr := PullRequest{
+			Title:       title,
+			RepoURL:     repoURL,
+			Files:       []string{},
+			ReleaseNote: entry.Note,
+			Manual:      true,
+			Author:      entry.Author,
+			// There is no PR to query GitHub for
+			detailsFetched: true,
+		}
+		for _, category := range entry.Categories {
+			pr.Labels = append(pr.Labels, struct {
+				Name string `json:"name"`
+			}{Name: category})
+		}
+		prs = append(prs, pr)
+	}
+
+	return prs, nil
+}
+
+// prRef returns the owner/repo#123 reference of a PR, or "manual entry" for
+// hand-written entries
+func prRef(pr PullRequest) string {
+	if pr.Manual {
+		return "manual entry"
+	}
+	return fmt.Sprintf("%s#%d", repoNameFromURL(pr.RepoURL), pr.Number)
+}
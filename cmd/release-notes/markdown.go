@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jespino/github-mm-release-notes/extract"
+	"github.com/jespino/github-mm-release-notes/internal/format"
+)
+
+// prURL returns the web URL of a PR
+func prURL(pr PullRequest) string {
+	repo := findRepository(pr.RepoURL)
+	return repo.forge().PullRequestURL(repo, pr.Number)
+}
+
+// renderMarkdown prints the release notes as Markdown ready for the
+// changelog docs: one bullet per note, linking back to its PR, under
+// headings for each type of change, such as new features and bug fixes.
+// PRs without a release note are left out.
+func renderMarkdown(prs []PullRequest) {
+	var withNotes []PullRequest
+	repos := make(map[string]bool)
+	for _, pr := range prs {
+		if !extract.HasReleaseNote(prReleaseNote(pr)) {
+			continue
+		}
+		repos[pr.RepoURL] = true
+		for _, duplicate := range pr.Duplicates {
+			repos[duplicate.RepoURL] = true
+		}
+		withNotes = append(withNotes, pr)
+	}
+
+	if len(repos) == 0 {
+		fmt.Println("No release notes found.")
+		fmt.Println()
+		return
+	}
+
+	// The guess rate is for the reviewers, not part of the document
+	if guessed := countGuessedTypes(withNotes); guessed > 0 {
+		logger.Info("Type of change guessed, review the provisional entries", "guessed", guessed, "total", len(withNotes))
+	}
+	for _, group := range groupPRsByType(withNotes) {
+		fmt.Printf("### %s\n\n", group.Type)
+		for _, pr := range group.PRs {
+			// Keep multi-line notes inside the bullet, a line each
+			note := format.MarkdownBullet(releaseNoteForPR(pr))
+			marker := provisionalMarker(pr, true)
+			if pr.Manual {
+				fmt.Printf("- %s%s\n", note, marker)
+				continue
+			}
+			// PR numbers are ambiguous when listing several repositories
+			fmt.Printf("- %s (%s)%s\n", note, markdownPRLinks(pr, len(repos) > 1), marker)
+		}
+		fmt.Println()
+		printStats(prsStats(group.PRs))
+	}
+}
+
+// markdownPRLinks returns the Markdown links to a PR and its duplicates,
+// e.g. "[#123](...)", prefixed with the repository when withRepo is set
+func markdownPRLinks(pr PullRequest, withRepo bool) string {
+	var links []string
+	for _, linked := range append([]PullRequest{pr}, pr.Duplicates...) {
+		linkText := fmt.Sprintf("#%d", linked.Number)
+		if withRepo {
+			linkText = repoNameFromURL(linked.RepoURL) + linkText
+		}
+		links = append(links, fmt.Sprintf("[%s](%s)", linkText, prURL(linked)))
+	}
+	return strings.Join(links, ", ")
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/jespino/github-mm-release-notes/internal/format"
+)
+
+// maxPostLength is the longest message, in characters, a Mattermost server
+// accepts by default. Longer notes are posted in several messages.
+const maxPostLength = 16383
+
+var (
+	// mattermostWebhookURL is the incoming webhook the notes are posted to
+	mattermostWebhookURL string
+	// mattermostURL is the server the notes are posted to through the REST
+	// API, in mattermostChannel
+	mattermostURL string
+	// mattermostChannel is the channel ID, or team/channel name, the notes are
+	// posted to. With a webhook, it overrides the channel of the webhook.
+	mattermostChannel string
+	// mattermostToken authenticates the REST API requests, from
+	// --mattermost-token or the MATTERMOST_TOKEN environment variable
+	mattermostToken string
+)
+
+// postToMattermost reports whether the generated notes are posted to a
+// Mattermost channel
+func postToMattermost() bool {
+	return mattermostWebhookURL != "" || mattermostURL != ""
+}
+
+// checkMattermostFlags fails when the flags to post to Mattermost are
+// incomplete
+func checkMattermostFlags() error {
+	switch {
+	case mattermostWebhookURL != "" && mattermostURL != "":
+		return fmt.Errorf("the --mattermost-webhook-url and --mattermost-url flags can't be used together")
+	case mattermostURL != "" && mattermostChannel == "":
+		return fmt.Errorf("the --mattermost-channel flag is required to post through the REST API")
+	case mattermostURL != "" && mattermostToken == "":
+		return fmt.Errorf("a token is required to post through the REST API, set one with --mattermost-token or the MATTERMOST_TOKEN environment variable")
+	case mattermostChannel != "" && !postToMattermost():
+		return fmt.Errorf("the --mattermost-channel flag requires --mattermost-webhook-url or --mattermost-url")
+	}
+	return nil
+}
+
+// postMattermostNotes posts the notes to the Mattermost channel, through the
+// incoming webhook or the REST API, split in several messages when they are
+// too long for one, in a thread with the REST API. Notes with profanity,
+// e-mail addresses or internal URLs are refused, as when publishing a GitHub
+// Release.
+func postMattermostNotes(notes string) error {
+	// Check for text that shouldn't be published
+	notes, err := screenNotes(notes)
+	if err != nil {
+		return err
+	}
+
+	messages := format.SplitMessage(notes, maxPostLength)
+	if len(messages) == 0 {
+		return nil
+	}
+
+	if mattermostWebhookURL != "" {
+		for _, message := range messages {
+			payload := map[string]string{"text": message}
+			if mattermostChannel != "" {
+				// Webhooks take the name of the channel, within their team
+				_, name, _ := strings.Cut(mattermostChannel, "/")
+				if name == "" {
+					name = mattermostChannel
+				}
+				payload["channel"] = name
+			}
+			if err := postMattermost(mattermostWebhookURL, payload, nil); err != nil {
+				return fmt.Errorf("error posting to the webhook: %w", err)
+			}
+		}
+		fmt.Printf("Release notes posted to Mattermost in %d message(s)\n", len(messages))
+		return nil
+	}
+
+	channelID, err := mattermostChannelID(mattermostChannel)
+	if err != nil {
+		return err
+	}
+	apiURL := strings.TrimSuffix(mattermostURL, "/") + "/api/v4/posts"
+	err = postThread(messages, func(message string, rootID string) (string, error) {
+		payload := map[string]string{"channel_id": channelID, "message": message}
+		if rootID != "" {
+			payload["root_id"] = rootID
+		}
+		var post struct {
+			ID string `json:"id"`
+		}
+		err := postMattermost(apiURL, payload, &post)
+		return post.ID, err
+	})
+	if err != nil {
+		return fmt.Errorf("error posting to channel %s: %w", mattermostChannel, err)
+	}
+	fmt.Printf("Release notes posted to Mattermost channel %s in %d message(s)\n", mattermostChannel, len(messages))
+	return nil
+}
+
+// mattermostChannelID returns the ID of a channel given as its ID or as
+// team/channel names, looking the names up with the REST API
+func mattermostChannelID(channel string) (string, error) {
+	team, name, ok := strings.Cut(channel, "/")
+	if !ok {
+		return channel, nil
+	}
+
+	var found struct {
+		ID string `json:"id"`
+	}
+	lookupURL := fmt.Sprintf("%s/api/v4/teams/name/%s/channels/name/%s", strings.TrimSuffix(mattermostURL, "/"), url.PathEscape(team), url.PathEscape(name))
+	if err := requestMattermost("GET", lookupURL, nil, &found); err != nil {
+		return "", fmt.Errorf("error looking up channel %s: %w", channel, err)
+	}
+	return found.ID, nil
+}
+
+// postMattermost posts a JSON payload to the Mattermost URL
+func postMattermost(postURL string, payload interface{}, v interface{}) error {
+	return requestMattermost("POST", postURL, payload, v)
+}
+
+// requestMattermost sends a request to Mattermost, with the token when the
+// REST API is used, decoding the JSON response into v when given
+func requestMattermost(method string, requestURL string, payload interface{}, v interface{}) error {
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, requestURL, body)
+	if err != nil {
+		return err
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if mattermostURL != "" {
+		req.Header.Set("Authorization", "Bearer "+mattermostToken)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		// Mattermost explains the errors in the message of the response
+		var apiError struct {
+			Message string `json:"message"`
+		}
+		if json.NewDecoder(resp.Body).Decode(&apiError) == nil && apiError.Message != "" {
+			return fmt.Errorf("Mattermost responded with code %d: %s", resp.StatusCode, apiError.Message)
+		}
+		return fmt.Errorf("Mattermost responded with code %d", resp.StatusCode)
+	}
+
+	if v != nil {
+		return json.NewDecoder(resp.Body).Decode(v)
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jespino/github-mm-release-notes/extract"
+	"github.com/jespino/github-mm-release-notes/internal/format"
+)
+
+// loadDiffSide returns the PRs with release notes of a side of the diff
+// command: a release set file, or the milestone of the repositories with the
+// title, open or closed
+func loadDiffSide(arg string, repoURLs []string) ([]PullRequest, string, error) {
+	if ext := filepath.Ext(arg); ext == ".yaml" || ext == ".yml" {
+		set, _, err := loadReleaseSet(arg)
+		if err != nil {
+			return nil, "", err
+		}
+		return set.pullRequests(), arg, nil
+	}
+
+	milestones, prs, err := getMilestonePRs(arg, repoURLs)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(milestones) > 0 {
+		return prs, arg, nil
+	}
+
+	for _, um := range getClosedMilestones(repoURLs, arg, arg) {
+		if um.Title != arg {
+			continue
+		}
+		prs, errs := fetchPRs(um.Milestones)
+		for i, err := range errs {
+			if err != nil {
+				return nil, "", fmt.Errorf("error getting PRs from %s: %w", repoNameFromURL(um.Milestones[i].RepoURL), err)
+			}
+		}
+		return prs, um.Title, nil
+	}
+	return nil, "", fmt.Errorf("no milestone %s found", arg)
+}
+
+// diffNotes returns the notes of the PRs by entry, leaving out the PRs
+// without a release note
+func diffNotes(prs []PullRequest) map[string]PullRequest {
+	kept, _ := separateNoneNotes(prs)
+	notes := make(map[string]PullRequest, len(kept))
+	for _, pr := range kept {
+		if extract.HasReleaseNote(prReleaseNote(pr)) {
+			notes[upgradeEntryKey(pr)] = pr
+		}
+	}
+	return notes
+}
+
+// sortedDiffKeys returns the keys of the entries sorted, so the diff is
+// stable
+func sortedDiffKeys(entries map[string]PullRequest) []string {
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// runDiff implements the diff command, which compares the notes of two
+// milestones, or release sets, listing the entries added, removed and
+// changed from the first to the second, e.g. after a milestone is retargeted
+// or backports move PRs around late in a release
+func runDiff() error {
+	args := flag.Args()
+	if len(args) != 2 {
+		return fmt.Errorf("usage: diff [--repo=...] <milestone or release set> <milestone or release set>, e.g. diff v9.7.0 v9.8.0")
+	}
+
+	repoURLs := allRepoURLs
+	if repoFlag != "" {
+		option, ok := findRepoOption(repoOptions(), repoFlag)
+		if !ok {
+			return fmt.Errorf("invalid repository %q", repoFlag)
+		}
+		repoURLs = repositoryURLs(option.Repos)
+	}
+
+	oldPRs, oldName, err := loadDiffSide(args[0], repoURLs)
+	if err != nil {
+		return err
+	}
+	newPRs, newName, err := loadDiffSide(args[1], repoURLs)
+	if err != nil {
+		return err
+	}
+	oldNotes := diffNotes(oldPRs)
+	newNotes := diffNotes(newPRs)
+
+	var added, removed, changed []string
+	for _, key := range sortedDiffKeys(newNotes) {
+		old, ok := oldNotes[key]
+		switch {
+		case !ok:
+			added = append(added, key)
+		case normalizeNote(prReleaseNote(old)) != normalizeNote(prReleaseNote(newNotes[key])):
+			changed = append(changed, key)
+		}
+	}
+	for _, key := range sortedDiffKeys(oldNotes) {
+		if _, ok := newNotes[key]; !ok {
+			removed = append(removed, key)
+		}
+	}
+
+	if outputFile != "" {
+		restore, err := redirectOutput(outputFile)
+		if err != nil {
+			return err
+		}
+		defer restore()
+	}
+
+	if len(added) == 0 && len(removed) == 0 && len(changed) == 0 {
+		fmt.Printf("The notes of %s and %s are the same\n", oldName, newName)
+		return nil
+	}
+
+	fmt.Printf("Notes changed from %s to %s:\n\n", oldName, newName)
+	printDiffEntries("Added", added, newNotes)
+	printDiffEntries("Removed", removed, oldNotes)
+	if len(changed) > 0 {
+		fmt.Printf("Changed (%d):\n", len(changed))
+		for _, key := range changed {
+			fmt.Printf("- %s:\n", prRef(newNotes[key]))
+			diff := format.LineDiff(prReleaseNote(oldNotes[key]), prReleaseNote(newNotes[key]))
+			for _, line := range strings.Split(strings.TrimSuffix(diff, "\n"), "\n") {
+				fmt.Printf("  %s\n", line)
+			}
+		}
+		fmt.Println()
+	}
+	return nil
+}
+
+// printDiffEntries prints the entries added to or removed from the notes
+func printDiffEntries(title string, keys []string, entries map[string]PullRequest) {
+	if len(keys) == 0 {
+		return
+	}
+
+	fmt.Printf("%s (%d):\n", title, len(keys))
+	for _, key := range keys {
+		pr := entries[key]
+		fmt.Printf("- %s: %s\n", prRef(pr), strings.ReplaceAll(prReleaseNote(pr), "\n", "\n  "))
+	}
+	fmt.Println()
+}
@@ -0,0 +1,40 @@
+package main
+
+// MilestoneName maps an internal milestone title to the public name of the
+// version, e.g. milestone "2025-08" to "Mattermost v10.11", for the monthly
+// releases whose milestones are named after the month
+type MilestoneName struct {
+	Milestone string `yaml:"milestone"`
+	Name      string `yaml:"name"`
+	// Date is the public release date, shown along with the name
+	Date string `yaml:"date"`
+}
+
+// findMilestoneName returns the mapping of a milestone in the configuration
+func findMilestoneName(milestoneTitle string) (MilestoneName, bool) {
+	for _, name := range config.MilestoneNames {
+		if name.Milestone == milestoneTitle {
+			return name, true
+		}
+	}
+	return MilestoneName{}, false
+}
+
+// milestoneDisplayName returns the public name of a milestone, used in the
+// file names, or the milestone title when it isn't mapped
+func milestoneDisplayName(milestoneTitle string) string {
+	if name, ok := findMilestoneName(milestoneTitle); ok && name.Name != "" {
+		return name.Name
+	}
+	return milestoneTitle
+}
+
+// milestoneHeader returns the public name of a milestone along with its
+// release date, if any, used in the headers of the notes
+func milestoneHeader(milestoneTitle string) string {
+	header := milestoneDisplayName(milestoneTitle)
+	if name, ok := findMilestoneName(milestoneTitle); ok && name.Date != "" {
+		header += " (" + name.Date + ")"
+	}
+	return header
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/jespino/github-mm-release-notes/extract"
+	"github.com/jespino/github-mm-release-notes/github"
+)
+
+// MissingNote is a merged PR without a release note in its description
+type MissingNote struct {
+	PR PullRequest
+}
+
+// hasMissingReleaseNote reports whether a PR description has no release
+// note in any of the supported formats. A NONE note is not missing.
+func hasMissingReleaseNote(body string) bool {
+	note := extractReleaseNote(body)
+	return note == extract.NoReleaseNote || note == extract.NoReleaseNoteInFormat
+}
+
+// findMissingNotes returns the merged PRs of the milestones, with or without
+// the release-note label, whose description has no release note, nor their
+// merge commit with --commit-notes
+func findMissingNotes(milestones []Milestone) ([]MissingNote, error) {
+	var missing []MissingNote
+	for _, milestone := range milestones {
+		url := fmt.Sprintf("%s/issues?milestone=%d&state=closed", milestone.RepoURL, milestone.Number)
+		// The issues API only sets pull_request for PRs
+		prs, err := github.GetAllPages[PullRequest](apiClient, url)
+		if err != nil {
+			return nil, fmt.Errorf("error getting PRs from %s: %w", repoNameFromURL(milestone.RepoURL), err)
+		}
+
+		for _, pr := range prs {
+			if pr.PullRequest == nil || pr.PullRequest.MergedAt == nil {
+				continue
+			}
+			if !hasMissingReleaseNote(pr.Body) {
+				continue
+			}
+			pr.RepoURL = milestone.RepoURL
+			if commitNotes {
+				trailer, err := commitTrailerNote(pr)
+				if err != nil {
+					return nil, err
+				}
+				if trailer != "" {
+					continue
+				}
+			}
+			missing = append(missing, MissingNote{PR: pr})
+		}
+	}
+
+	return missing, nil
+}
+
+// checkMissingNotes implements --check-missing, listing the merged PRs of the
+// milestones without a release note so the release managers can chase their
+// authors, and fails if there is any
+func checkMissingNotes(milestones []Milestone) error {
+	missing, err := findMissingNotes(milestones)
+	if err != nil {
+		return err
+	}
+	if len(missing) == 0 {
+		fmt.Println("Every merged PR of the milestone has a release note.")
+		return nil
+	}
+
+	fmt.Println("Merged PRs without a release note in their description:")
+	fmt.Println()
+	for _, m := range missing {
+		author := ""
+		if m.PR.User.Login != "" {
+			author = " (@" + m.PR.User.Login + ")"
+		}
+		fmt.Printf("- %s: %s%s\n", prRef(m.PR), m.PR.Title, author)
+	}
+	fmt.Println()
+
+	return fmt.Errorf("%d merged PRs are missing a release note", len(missing))
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// isMilestonePattern reports whether a --milestone value is a glob, e.g.
+// "v9.*", rather than a title
+func isMilestonePattern(value string) bool {
+	return strings.ContainsAny(value, "*?[")
+}
+
+// multipleMilestones reports whether the notes of several milestones were
+// requested, with --milestone given several times or as a glob
+func multipleMilestones() bool {
+	return len(milestoneFlags) > 1 || (len(milestoneFlags) == 1 && isMilestonePattern(milestoneFlags[0]))
+}
+
+// matchMilestones returns the milestones selected by the --milestone values,
+// in the order of the values. The milestones matching a glob are sorted by
+// version, e.g. v9.10.0 after v9.9.0.
+func matchMilestones(milestones []Milestone, values []string) ([]Milestone, error) {
+	var selected []Milestone
+	seen := make(map[string]bool)
+	for _, value := range values {
+		var matches []Milestone
+		for _, milestone := range milestones {
+			if ok, _ := path.Match(value, milestone.Title); ok || milestone.Title == value {
+				matches = append(matches, milestone)
+			}
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("no open milestone matching %q found", value)
+		}
+
+		sort.SliceStable(matches, func(i, j int) bool {
+			return compareVersionTitles(matches[i].Title, matches[j].Title) < 0
+		})
+		for _, milestone := range matches {
+			if !seen[milestone.Title] {
+				seen[milestone.Title] = true
+				selected = append(selected, milestone)
+			}
+		}
+	}
+	return selected, nil
+}
+
+// compareVersionTitles compares milestone titles comparing their runs of
+// digits as numbers, so versions sort naturally
+func compareVersionTitles(a, b string) int {
+	for a != "" && b != "" {
+		aDigits, bDigits := unicode.IsDigit(rune(a[0])), unicode.IsDigit(rune(b[0]))
+		if aDigits && bDigits {
+			aRun, bRun := leadingDigits(a), leadingDigits(b)
+			aNumber, _ := strconv.Atoi(aRun)
+			bNumber, _ := strconv.Atoi(bRun)
+			if aNumber != bNumber {
+				if aNumber < bNumber {
+					return -1
+				}
+				return 1
+			}
+			a, b = a[len(aRun):], b[len(bRun):]
+			continue
+		}
+
+		if a[0] != b[0] {
+			if a[0] < b[0] {
+				return -1
+			}
+			return 1
+		}
+		a, b = a[1:], b[1:]
+	}
+	return len(a) - len(b)
+}
+
+// leadingDigits returns the run of digits s starts with
+func leadingDigits(s string) string {
+	end := 0
+	for end < len(s) && unicode.IsDigit(rune(s[end])) {
+		end++
+	}
+	return s[:end]
+}
+
+// milestoneTargets returns the milestones matching a selected milestone in
+// each repository, unified by title when there are several, without the
+// ones excluded by the configuration
+func milestoneTargets(selected Milestone, unifiedMilestones []UnifiedMilestone) []Milestone {
+	targetMilestones := []Milestone{selected}
+	for _, um := range unifiedMilestones {
+		if um.Title == selected.Title {
+			targetMilestones = um.Milestones
+			break
+		}
+	}
+	return excludeMilestones(targetMilestones)
+}
+
+// checkMultipleMilestonesFlags fails when several milestones are requested
+// along with the flags writing a file per milestone
+func checkMultipleMilestonesFlags() error {
+	switch {
+	case rcNumber > 0:
+		return fmt.Errorf("the --rc flag can't be used with several milestones")
+	case releaseSetFile != "":
+		return fmt.Errorf("the --release-set flag can't be used with several milestones")
+	case checklistFile != "":
+		return fmt.Errorf("the --checklist flag can't be used with several milestones")
+	case galleryFile != "":
+		return fmt.Errorf("the --gallery flag can't be used with several milestones")
+	case publishReleaseTag != "":
+		return fmt.Errorf("the --publish-release flag can't be used with several milestones")
+	}
+	return nil
+}
+
+// generateMultipleReleaseNotes prints the release notes of several
+// milestones as a single document, with a section per milestone
+func generateMultipleReleaseNotes(selected []Milestone, unifiedMilestones []UnifiedMilestone, repoName string) error {
+	if err := checkMultipleMilestonesFlags(); err != nil {
+		return err
+	}
+
+	var titles []string
+	for _, milestone := range selected {
+		titles = append(titles, milestone.Title)
+	}
+	fmt.Printf("\nSelected milestones: %s\n\n", strings.Join(titles, ", "))
+
+	if checkMissing {
+		var targetMilestones []Milestone
+		for _, milestone := range selected {
+			targetMilestones = append(targetMilestones, milestoneTargets(milestone, unifiedMilestones)...)
+		}
+		return checkMissingNotes(githubMilestones(targetMilestones, "--check-missing"))
+	}
+
+	// The sections of every milestone go to the same file
+	if outputFile != "" {
+		restore, err := redirectOutput(outputFile)
+		if err != nil {
+			return fmt.Errorf("error creating output file: %w", err)
+		}
+		defer restore()
+		outputFile = ""
+	}
+
+	// The PRs of the milestones are fetched in parallel, the notes are then
+	// generated in order
+	targets := make([][]Milestone, len(selected))
+	requestsPerMilestone := 0
+	for i, milestone := range selected {
+		targets[i] = milestoneTargets(milestone, unifiedMilestones)
+		requestsPerMilestone = max(requestsPerMilestone, estimatedRequests(targets[i]))
+	}
+	prSets := make([][]PullRequest, len(selected))
+	var mu sync.Mutex
+	var failed []string
+	var g errgroup.Group
+	g.SetLimit(plannedWorkers(parallelMilestones, requestsPerMilestone))
+	for i, milestone := range selected {
+		g.Go(func() (err error) {
+			defer recoverGoroutine(&err)
+
+			prs, errs := fetchPRs(targets[i])
+
+			mu.Lock()
+			defer mu.Unlock()
+			for j, err := range errs {
+				if err != nil {
+					failed = append(failed, fmt.Sprintf("%s in %s", milestone.Title, repoNameFromURL(targets[i][j].RepoURL)))
+					logger.Error("Error getting PRs", "repo", repoNameFromURL(targets[i][j].RepoURL), "milestone", milestone.Title, "error", err)
+				}
+			}
+			prSets[i] = prs
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	// A document missing the notes of a repository would look complete
+	if len(failed) > 0 {
+		sort.Slice(failed, func(i, j int) bool {
+			return compareVersionTitles(failed[i], failed[j]) < 0
+		})
+		return fmt.Errorf("error getting the PRs of %s", strings.Join(failed, ", "))
+	}
+
+	for i, milestone := range selected {
+		if outputFormat == formatMarkdown {
+			fmt.Printf("## %s\n\n", milestoneHeader(milestone.Title))
+		}
+		if err := generateReleaseNotes(prSets[i], milestone.Title, targets[i], repoName); err != nil {
+			return fmt.Errorf("error generating the notes of %s: %w", milestone.Title, err)
+		}
+	}
+
+	return nil
+}
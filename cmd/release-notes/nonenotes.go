@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/jespino/github-mm-release-notes/extract"
+)
+
+// separateNoneNotes removes the PRs whose release note is "NONE" from the
+// list and returns them, so they are flagged instead of printed as notes
+func separateNoneNotes(prs []PullRequest) ([]PullRequest, []PullRequest) {
+	var kept, none []PullRequest
+	for _, pr := range prs {
+		if extract.IsNone(prReleaseNote(pr)) {
+			none = append(none, pr)
+		} else {
+			kept = append(kept, pr)
+		}
+	}
+	return kept, none
+}
+
+// printNoneNotes prints the appendix listing the PRs left out of the notes
+// because their release note is "NONE"
+func printNoneNotes(prs []PullRequest) {
+	if len(prs) == 0 {
+		return
+	}
+
+	out := reviewOutput()
+	fmt.Fprintln(out, "PRs with a NONE release note (excluded from the release notes):")
+	fmt.Fprintln(out)
+	for _, pr := range prs {
+		fmt.Fprintf(out, "- %s: %s\n", prRef(pr), pr.Title)
+	}
+	fmt.Fprintln(out)
+}
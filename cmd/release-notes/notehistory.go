@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// graphQLURL is the endpoint of the GitHub GraphQL API
+const graphQLURL = githubAPIURL + "/graphql"
+
+// NoteHistory tells when the release note of a PR was last touched. Zero
+// times are unknown.
+type NoteHistory struct {
+	LabeledAt    time.Time // When the release-note label was last added
+	BodyEditedAt time.Time // When the PR description was last edited
+}
+
+// getBodyEditedAt returns when the description of a PR was last edited, or a
+// zero time if it was never edited. The REST API doesn't expose body edits,
+// so this uses the GraphQL API, which requires a token.
+func getBodyEditedAt(repoURL string, number int) (time.Time, error) {
+	owner, name, _ := strings.Cut(repoNameFromURL(repoURL), "/")
+
+	request := map[string]interface{}{
+		"query": `query($owner: String!, $name: String!, $number: Int!) {
+			repository(owner: $owner, name: $name) {
+				pullRequest(number: $number) { lastEditedAt }
+			}
+		}`,
+		"variables": map[string]interface{}{"owner": owner, "name": name, "number": number},
+	}
+
+	var response struct {
+		Data struct {
+			Repository struct {
+				PullRequest struct {
+					LastEditedAt *time.Time `json:"lastEditedAt"`
+				} `json:"pullRequest"`
+			} `json:"repository"`
+		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := apiClient.DoJSON("POST", graphQLURL, request, &response); err != nil {
+		return time.Time{}, err
+	}
+	if len(response.Errors) > 0 {
+		return time.Time{}, fmt.Errorf("GraphQL error: %s", response.Errors[0].Message)
+	}
+
+	if editedAt := response.Data.Repository.PullRequest.LastEditedAt; editedAt != nil {
+		return *editedAt, nil
+	}
+	return time.Time{}, nil
+}
+
+// fetchNoteHistory populates the NoteHistory field of the PRs in place, from
+// their timeline and, when a token is available, their last body edit
+func fetchNoteHistory(prs []PullRequest) error {
+	for i := range prs {
+		if prs[i].Manual || !isGitHubRepo(prs[i].RepoURL) {
+			continue
+		}
+		events, err := getTimeline(prs[i].RepoURL, prs[i].Number)
+		if err != nil {
+			return fmt.Errorf("error getting timeline for PR #%d: %w", prs[i].Number, err)
+		}
+
+		var history NoteHistory
+		labels := releaseNoteLabels(prs[i].RepoURL)
+		for _, event := range events {
+			if event.Event != "labeled" || event.Label == nil {
+				continue
+			}
+			for _, label := range labels {
+				if strings.EqualFold(event.Label.Name, label) {
+					history.LabeledAt = event.CreatedAt
+				}
+			}
+		}
+
+		if authToken != "" {
+			if history.BodyEditedAt, err = getBodyEditedAt(prs[i].RepoURL, prs[i].Number); err != nil {
+				return fmt.Errorf("error getting last edit for PR #%d: %w", prs[i].Number, err)
+			}
+		}
+
+		prs[i].NoteHistory = &history
+	}
+
+	return nil
+}
+
+// needsReReview reports whether the note was labeled or edited after the
+// docs review cutoff
+func needsReReview(history NoteHistory, cutoff time.Time) bool {
+	if cutoff.IsZero() {
+		return false
+	}
+	return history.LabeledAt.After(cutoff) || history.BodyEditedAt.After(cutoff)
+}
+
+// formatNoteHistory describes the note history of a PR in a single line
+func formatNoteHistory(history NoteHistory, cutoff time.Time) string {
+	date := func(t time.Time) string {
+		if t.IsZero() {
+			return "unknown"
+		}
+		return t.Format("2006-01-02 15:04")
+	}
+
+	body := "never"
+	if !history.BodyEditedAt.IsZero() {
+		body = date(history.BodyEditedAt)
+	} else if authToken == "" {
+		body = "unknown (requires a token)"
+	}
+
+	line := fmt.Sprintf("label added %s, body last edited %s", date(history.LabeledAt), body)
+	if needsReReview(history, cutoff) {
+		line += " - CHANGED AFTER THE REVIEW CUTOFF, needs re-review"
+	}
+
+	return line
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"unicode"
+	"unicode/utf8"
+)
+
+// NoteTemplate rephrases the release notes of the PRs with any of its
+// labels, for a consistent voice across the notes of a category, e.g.
+// "Fixed an issue where {{lowerFirst .Note}}" for bug fixes
+type NoteTemplate struct {
+	Labels   []string `yaml:"labels"`
+	Template string   `yaml:"template"`
+
+	tmpl *template.Template
+}
+
+// NoteTemplateData holds the placeholders available in note templates
+type NoteTemplateData struct {
+	Note   string
+	Title  string
+	Repo   string
+	Number int
+}
+
+var noteTemplateFuncs = template.FuncMap{
+	"lowerFirst": lowerFirst,
+	"trimPeriod": func(s string) string { return strings.TrimSuffix(s, ".") },
+}
+
+// lowerFirst lowercases the first letter of s, to embed a note in a sentence
+func lowerFirst(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	if r == utf8.RuneError {
+		return s
+	}
+	return string(unicode.ToLower(r)) + s[size:]
+}
+
+// compileNoteTemplates parses the note templates of the configuration
+func compileNoteTemplates(templates []NoteTemplate) error {
+	for i := range templates {
+		tmpl, err := template.New(fmt.Sprintf("note_templates[%d]", i)).Funcs(noteTemplateFuncs).Parse(templates[i].Template)
+		if err != nil {
+			return err
+		}
+		templates[i].tmpl = tmpl
+	}
+	return nil
+}
+
+// applyNoteTemplate rephrases a release note through the first template
+// matching the labels of the PR. Notes already starting with the fixed text
+// of the template, e.g. "Fixed an issue where", are kept as written.
+func applyNoteTemplate(pr PullRequest, releaseNote string) string {
+	for _, noteTemplate := range config.NoteTemplates {
+		if noteTemplate.tmpl == nil || !hasAnyLabel(pr, noteTemplate.Labels) {
+			continue
+		}
+
+		prefix, _, _ := strings.Cut(noteTemplate.Template, "{{")
+		if prefix = strings.TrimSpace(prefix); prefix != "" && strings.HasPrefix(strings.ToLower(releaseNote), strings.ToLower(prefix)) {
+			return releaseNote
+		}
+
+		var buf strings.Builder
+		data := NoteTemplateData{Note: releaseNote, Title: pr.Title, Repo: repoNameFromURL(pr.RepoURL), Number: pr.Number}
+		if err := noteTemplate.tmpl.Execute(&buf, data); err != nil {
+			logger.Warn("Error applying note template, keeping the note as written", "pr", pr.Number, "error", err)
+			return releaseNote
+		}
+		return buf.String()
+	}
+
+	return releaseNote
+}
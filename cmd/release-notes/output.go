@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"sync"
+)
+
+// reviewOutput returns where the details for the reviewers printed among
+// the notes, such as the stats of the sections, are printed: along with the
+// notes, except in the HTML page and in the notes to publish, which they
+// would end up in
+func reviewOutput() io.Writer {
+	if outputFormat == formatHTML || publishReleaseTag != "" || postToMattermost() {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+// redirectOutput sends the notes printed to stdout to the file at path, set
+// with --output, so they are not mixed with the prompts. The messages of the
+// logger still go to the terminal. The returned function restores stdout,
+// and can be called more than once.
+func redirectOutput(path string) (func(), error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	stdout := os.Stdout
+	os.Stdout = file
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			os.Stdout = stdout
+			if err := file.Close(); err != nil {
+				logger.Error("Error writing output", "file", path, "error", err)
+				return
+			}
+			logger.Info("Release notes written", "file", path)
+		})
+	}, nil
+}
+
+// captureOutput keeps a copy of what is printed to stdout from now on, still
+// printing it, e.g. to publish the generated notes. The returned function
+// stops the capture and returns the text, and can be called more than once.
+func captureOutput() (func() string, error) {
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+
+	stdout := os.Stdout
+	os.Stdout = writer
+
+	var captured bytes.Buffer
+	done := make(chan struct{})
+	go func() {
+		io.Copy(io.MultiWriter(stdout, &captured), reader)
+		close(done)
+	}()
+
+	var once sync.Once
+	return func() string {
+		once.Do(func() {
+			os.Stdout = stdout
+			writer.Close()
+			<-done
+			reader.Close()
+		})
+		return captured.String()
+	}, nil
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/template"
+
+	"github.com/jespino/github-mm-release-notes/extract"
+)
+
+// TemplateData is the data available to the --template files
+type TemplateData struct {
+	// Milestone is the title of the notes, e.g. "v10.5.0" or the public
+	// version name of the milestone
+	Milestone string
+	// Repos are the owner/repo names of the repositories of the entries
+	Repos []string
+	// Entries are the PRs with a release note, in the order of the notes
+	Entries []TemplateEntry
+	// Groups are the entries by type of change, leaving out the empty ones
+	Groups []TemplateGroup
+}
+
+// TemplateEntry is a PR, or a hand-written entry, of the --template data
+type TemplateEntry struct {
+	Repo        string
+	Number      int
+	Title       string
+	Note        string
+	URL         string // Empty for hand-written entries
+	Labels      []string
+	Type        string
+	Provisional bool // The type was guessed
+	Manual      bool
+	Author      string
+	// Duplicates are the PRs with the same note merged into this entry with
+	// --dedup
+	Duplicates []TemplateEntry
+}
+
+// TemplateGroup is a type of change and its entries
+type TemplateGroup struct {
+	Type    string
+	Entries []TemplateEntry
+}
+
+// templateEntry returns the --template data of a PR
+func templateEntry(pr PullRequest) TemplateEntry {
+	changeType, provisional := changeType(pr)
+	entry := TemplateEntry{
+		Repo:        repoNameFromURL(pr.RepoURL),
+		Number:      pr.Number,
+		Title:       pr.Title,
+		Note:        releaseNoteForPR(pr),
+		Type:        changeType,
+		Provisional: provisional,
+		Manual:      pr.Manual,
+		Author:      pr.Author,
+	}
+	if !pr.Manual {
+		entry.URL = prURL(pr)
+	}
+	for _, label := range pr.Labels {
+		entry.Labels = append(entry.Labels, label.Name)
+	}
+	for _, duplicate := range pr.Duplicates {
+		entry.Duplicates = append(entry.Duplicates, templateEntry(duplicate))
+	}
+	return entry
+}
+
+// renderTemplate prints the release notes through the Go text/template file
+// given with --template, for teams with their own changelog style. The
+// functions of the note templates are available too.
+func renderTemplate(prs []PullRequest, milestoneTitle string) error {
+	tmpl, err := template.New(filepath.Base(templateFile)).Funcs(noteTemplateFuncs).ParseFiles(templateFile)
+	if err != nil {
+		return fmt.Errorf("error parsing the template: %w", err)
+	}
+
+	var withNotes []PullRequest
+	data := TemplateData{Milestone: milestoneTitle}
+	for _, pr := range prs {
+		if !extract.HasReleaseNote(prReleaseNote(pr)) {
+			continue
+		}
+		withNotes = append(withNotes, pr)
+		data.Entries = append(data.Entries, templateEntry(pr))
+
+		if repo := repoNameFromURL(pr.RepoURL); !pr.Manual && !containsString(data.Repos, repo) {
+			data.Repos = append(data.Repos, repo)
+		}
+	}
+
+	for _, group := range groupPRsByType(withNotes) {
+		templateGroup := TemplateGroup{Type: group.Type}
+		for _, pr := range group.PRs {
+			templateGroup.Entries = append(templateGroup.Entries, templateEntry(pr))
+		}
+		data.Groups = append(data.Groups, templateGroup)
+	}
+
+	if err := tmpl.Execute(os.Stdout, data); err != nil {
+		return fmt.Errorf("error rendering the template: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// fuzzyScore scores how well a query matches a text, ignoring case: 2 when
+// the query is a substring of the text, 1 when its characters appear in the
+// text in order, e.g. "98" in "v9.8.0", and 0 when it doesn't match
+func fuzzyScore(query string, text string) int {
+	query = strings.ToLower(query)
+	text = strings.ToLower(text)
+	if strings.Contains(text, query) {
+		return 2
+	}
+
+	remaining := []rune(query)
+	for _, c := range text {
+		if len(remaining) > 0 && c == remaining[0] {
+			remaining = remaining[1:]
+		}
+	}
+	if len(remaining) == 0 {
+		return 1
+	}
+	return 0
+}
+
+// filterMilestones returns the milestones whose title or public name match
+// the query, the closest matches first
+func filterMilestones(milestones []Milestone, query string) []Milestone {
+	type match struct {
+		milestone Milestone
+		score     int
+	}
+	var matches []match
+	for _, milestone := range milestones {
+		score := max(fuzzyScore(query, milestone.Title), fuzzyScore(query, milestoneDisplayName(milestone.Title)))
+		if score > 0 {
+			matches = append(matches, match{milestone, score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score > matches[j].score
+	})
+
+	filtered := make([]Milestone, len(matches))
+	for i, m := range matches {
+		filtered[i] = m.milestone
+	}
+	return filtered
+}
+
+// printMilestoneMenu prints the numbered milestones to select from
+func printMilestoneMenu(milestones []Milestone) {
+	for i, milestone := range milestones {
+		name := milestone.Title
+		if displayName := milestoneDisplayName(milestone.Title); displayName != milestone.Title {
+			name += " (" + displayName + ")"
+		}
+		fmt.Printf("%d: %s\n", i+1, name)
+	}
+}
+
+// pickMilestone asks the user for a milestone, by its number in the menu or
+// by typing part of its title to filter the menu, e.g. "9.8" for v9.8.0. A
+// filter matching a single milestone, or the exact title, selects it, and an
+// empty line shows every milestone again. It returns false when the input
+// ends without a selection.
+func pickMilestone(reader *bufio.Reader, milestones []Milestone) (Milestone, bool) {
+	shown := milestones
+	fmt.Println("Available milestones:")
+	printMilestoneMenu(shown)
+
+	for {
+		fmt.Print("\nSelect a milestone (number, or type to filter): ")
+		input, err := reader.ReadString('\n')
+		input = strings.TrimSpace(input)
+		if input == "" {
+			if err != nil {
+				return Milestone{}, false
+			}
+			shown = milestones
+			printMilestoneMenu(shown)
+			continue
+		}
+
+		if index, convErr := strconv.Atoi(input); convErr == nil {
+			if index >= 1 && index <= len(shown) {
+				return shown[index-1], true
+			}
+			fmt.Printf("Invalid selection, must be between 1 and %d\n", len(shown))
+		} else {
+			for _, milestone := range milestones {
+				if strings.EqualFold(milestone.Title, input) {
+					return milestone, true
+				}
+			}
+
+			filtered := filterMilestones(milestones, input)
+			switch len(filtered) {
+			case 0:
+				fmt.Printf("No milestone matches %q\n", input)
+			case 1:
+				return filtered[0], true
+			default:
+				shown = filtered
+				printMilestoneMenu(shown)
+			}
+		}
+
+		if err != nil {
+			return Milestone{}, false
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// loadPinnedOrder reads a pinned-order file, with one PR per line in the
+// owner/repo#123 form, and returns the position of each PR in it. Blank
+// lines and lines starting with # are ignored.
+func loadPinnedOrder(path string) (map[string]int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	order := make(map[string]int)
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		repo, number, err := parsePRRef(line)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, lineNumber, err)
+		}
+		key := fmt.Sprintf("%s#%d", repo, number)
+		if _, ok := order[key]; !ok {
+			order[key] = len(order)
+		}
+	}
+
+	return order, scanner.Err()
+}
+
+// pinPRs moves the PRs of the pinned-order file to the front, in the order of
+// the file, keeping the order of the rest. As sections list the PRs in the
+// order they are given, pinned PRs head their sections regardless of the
+// default sorting.
+func pinPRs(prs []PullRequest, order map[string]int) {
+	sort.SliceStable(prs, func(i, j int) bool {
+		pi, pinnedI := order[prKey(prs[i])]
+		pj, pinnedJ := order[prKey(prs[j])]
+		if pinnedI && pinnedJ {
+			return pi < pj
+		}
+		return pinnedI && !pinnedJ
+	})
+}
+
+// applyPinnedOrder reorders the PRs by the pinned-order file given with
+// --pin-file, if any
+func applyPinnedOrder(prs []PullRequest) error {
+	if pinFile == "" {
+		return nil
+	}
+
+	order, err := loadPinnedOrder(pinFile)
+	if err != nil {
+		return fmt.Errorf("error reading pinned-order file: %w", err)
+	}
+	pinPRs(prs, order)
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/jespino/github-mm-release-notes/extract"
+)
+
+// extractorEdited is recorded as the extractor of notes edited by hand
+const extractorEdited = "edited"
+
+// Provenance records where the note of an entry came from, so every published
+// sentence can be traced back to its source
+type Provenance struct {
+	Repo string `yaml:"repo" json:"repo"`
+	PR   int    `yaml:"pr" json:"pr"`
+	// Extractor is the release note format that matched the PR description,
+	// empty when none did
+	Extractor   string    `yaml:"extractor" json:"extractor"`
+	ExtractedAt time.Time `yaml:"extracted_at" json:"extracted_at"`
+	// BodySHA256 is the hash of the PR description the note was extracted
+	// from, to detect later edits
+	BodySHA256 string `yaml:"body_sha256" json:"body_sha256"`
+	// Author is who wrote a hand-written entry
+	Author string `yaml:"author,omitempty" json:"author,omitempty"`
+}
+
+// newProvenance returns the provenance of the note of a PR extracted now
+func newProvenance(pr PullRequest, extractedAt time.Time) Provenance {
+	provenance := Provenance{
+		Repo:        repoNameFromURL(pr.RepoURL),
+		PR:          pr.Number,
+		ExtractedAt: extractedAt,
+		BodySHA256:  bodySHA256(pr.Body),
+	}
+
+	if pr.Manual {
+		provenance.Extractor = extractorManual
+		provenance.Author = pr.Author
+	} else if pr.CommitNote {
+		provenance.Extractor = extract.ExtractorCommitTrailer
+	} else if pr.ReleaseNote != "" {
+		provenance.Extractor = extractorEdited
+	} else {
+		_, provenance.Extractor = extract.ReleaseNote(pr.Body)
+	}
+
+	return provenance
+}
+
+// bodySHA256 returns the hex-encoded SHA-256 of a PR description
+func bodySHA256(body string) string {
+	sum := sha256.Sum256([]byte(body))
+	return hex.EncodeToString(sum[:])
+}
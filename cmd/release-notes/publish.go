@@ -0,0 +1,190 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jespino/github-mm-release-notes/github"
+	"github.com/jespino/github-mm-release-notes/internal/format"
+)
+
+// Release is a GitHub Release
+type Release struct {
+	ID         int64  `json:"id"`
+	TagName    string `json:"tag_name"`
+	Name       string `json:"name"`
+	Body       string `json:"body"`
+	Draft      bool   `json:"draft"`
+	Prerelease bool   `json:"prerelease"`
+	HTMLURL    string `json:"html_url"`
+	UploadURL  string `json:"upload_url"`
+	Assets     []struct {
+		ID   int64  `json:"id"`
+		Name string `json:"name"`
+	} `json:"assets"`
+}
+
+// getReleaseByTag returns the release of a repository for a tag, or nil if
+// there is none
+func getReleaseByTag(repoURL string, tag string) (*Release, error) {
+	var release Release
+	err := apiClient.GetJSON(fmt.Sprintf("%s/releases/tags/%s", repoURL, url.PathEscape(tag)), &release)
+
+	var apiErr *github.APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &release, nil
+}
+
+// runPublish implements the publish command, which creates or updates the
+// GitHub Release of a tag with the notes read from a file. When given the
+// release set the notes were rendered from, publishing stale notes requires
+// --force.
+func runPublish() error {
+	if publishTag == "" {
+		return fmt.Errorf("the --github-release flag with the release tag is required")
+	}
+	if notesFile == "" {
+		return fmt.Errorf("the --notes-file flag with the notes to publish is required")
+	}
+
+	notes, err := os.ReadFile(notesFile)
+	if err != nil {
+		return err
+	}
+
+	// Check the notes were rendered from up to date descriptions
+	if releaseSetFile != "" {
+		if err := checkReleaseSetFresh(releaseSetFile); err != nil {
+			return err
+		}
+	}
+
+	return publishRelease(publishTag, string(notes))
+}
+
+// publishRelease creates or updates the GitHub Release of a tag in the
+// --release-repo with the notes as its body, for the publish command and
+// --publish-release. Overwriting the body of a release that is already
+// published requires --force, and the changes are shown before doing so.
+// Notes with profanity, e-mail addresses or internal URLs are refused.
+func publishRelease(tag string, notes string) error {
+	// Check for text that shouldn't be published
+	body, err := screenNotes(notes)
+	if err != nil {
+		return err
+	}
+
+	repoURL := repoURLFromName(releaseRepo)
+	existing, err := getReleaseByTag(repoURL, tag)
+	if err != nil {
+		return fmt.Errorf("error getting release %s: %w", tag, err)
+	}
+
+	if existing == nil {
+		var created Release
+		request := map[string]interface{}{
+			"tag_name":   tag,
+			"name":       tag,
+			"body":       body,
+			"draft":      publishDraft,
+			"prerelease": rcNumber > 0,
+		}
+		if err := apiClient.DoJSON("POST", repoURL+"/releases", request, &created); err != nil {
+			return fmt.Errorf("error creating release %s: %w", tag, err)
+		}
+		fmt.Printf("Created release %s: %s\n", tag, created.HTMLURL)
+		return uploadAssets(repoURL, &created, assetFiles)
+	}
+
+	if existing.Body == body {
+		fmt.Printf("Release %s is already up to date: %s\n", tag, existing.HTMLURL)
+		return uploadAssets(repoURL, existing, assetFiles)
+	}
+
+	// Protect releases that were already announced
+	if !existing.Draft {
+		fmt.Printf("Release %s is already published. Changes to its notes:\n\n", tag)
+		fmt.Println(format.LineDiff(existing.Body, body))
+		if !forcePublish {
+			return fmt.Errorf("refusing to overwrite the published release %s, re-run with --force to update it", tag)
+		}
+	}
+
+	var updated Release
+	request := map[string]interface{}{"body": body, "prerelease": rcNumber > 0}
+	if err := apiClient.DoJSON("PATCH", fmt.Sprintf("%s/releases/%d", repoURL, existing.ID), request, &updated); err != nil {
+		return fmt.Errorf("error updating release %s: %w", tag, err)
+	}
+	fmt.Printf("Updated release %s: %s\n", tag, updated.HTMLURL)
+
+	return uploadAssets(repoURL, &updated, assetFiles)
+}
+
+// uploadAssets attaches the files to the release, replacing the existing
+// assets with the same name. A replacement is uploaded under a temporary name
+// and only swapped in once the upload succeeded, so a failed upload leaves
+// the previous asset in place.
+func uploadAssets(repoURL string, release *Release, files []string) error {
+	for _, file := range files {
+		name := filepath.Base(file)
+
+		var existingID int64
+		for _, asset := range release.Assets {
+			if asset.Name == name {
+				existingID = asset.ID
+			}
+		}
+
+		uploadName := name
+		if existingID != 0 {
+			uploadName = "new-" + name
+		}
+
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+
+		contentType := mime.TypeByExtension(filepath.Ext(file))
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+
+		// The upload URL is a URI template like ".../assets{?name,label}"
+		uploadURL, _, _ := strings.Cut(release.UploadURL, "{")
+		uploadURL += "?name=" + url.QueryEscape(uploadName)
+
+		var uploaded struct {
+			ID int64 `json:"id"`
+		}
+		_, err = apiClient.Do("POST", uploadURL, contentType, f, &uploaded)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("error uploading asset %s: %w", name, err)
+		}
+
+		if existingID != 0 {
+			if err := apiClient.DoJSON("DELETE", fmt.Sprintf("%s/releases/assets/%d", repoURL, existingID), nil, nil); err != nil {
+				return fmt.Errorf("error replacing asset %s, the new one was uploaded as %s: %w", name, uploadName, err)
+			}
+			rename := map[string]interface{}{"name": name}
+			if err := apiClient.DoJSON("PATCH", fmt.Sprintf("%s/releases/assets/%d", repoURL, uploaded.ID), rename, nil); err != nil {
+				return fmt.Errorf("error renaming asset %s to %s: %w", uploadName, name, err)
+			}
+		}
+		fmt.Printf("Uploaded asset %s\n", name)
+	}
+
+	return nil
+}
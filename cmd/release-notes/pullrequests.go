@@ -0,0 +1,126 @@
+package main
+
+import (
+	"strings"
+	"time"
+
+	"github.com/jespino/github-mm-release-notes/extract"
+)
+
+// GitHub API structures
+type Milestone struct {
+	Number      int        `json:"number"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	CreatedAt   time.Time  `json:"created_at"`
+	DueOn       *time.Time `json:"due_on"`
+	RepoURL     string     `json:"-"` // Internal field, not from API
+}
+
+// unifyMilestonesByName combines milestones with the same title/name across repositories
+func unifyMilestonesByName(milestoneSets ...[]Milestone) []UnifiedMilestone {
+	// Map to hold milestones by title
+	milestoneMap := make(map[string]*UnifiedMilestone)
+
+	// Process all milestone sets
+	for _, milestoneSet := range milestoneSets {
+		for _, milestone := range milestoneSet {
+			if existing, ok := milestoneMap[milestone.Title]; ok {
+				// Add to existing unified milestone
+				existing.Milestones = append(existing.Milestones, milestone)
+			} else {
+				// Create new unified milestone
+				milestoneMap[milestone.Title] = &UnifiedMilestone{
+					Title:       milestone.Title,
+					Description: milestone.Description,
+					Milestones:  []Milestone{milestone},
+				}
+			}
+		}
+	}
+
+	// Convert map to slice
+	result := make([]UnifiedMilestone, 0, len(milestoneMap))
+	for _, unified := range milestoneMap {
+		result = append(result, *unified)
+	}
+
+	return result
+}
+
+// UnifiedMilestone represents a milestone that may exist in multiple repositories
+type UnifiedMilestone struct {
+	Title       string      // Common name/title
+	Description string      // Description (from the first found milestone)
+	Milestones  []Milestone // Actual milestones from different repos
+}
+
+type PullRequest struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	Body   string `json:"body"`
+	// State is open or closed, for merged PRs too
+	State string `json:"state"`
+	// PullRequest holds the merge time in the issues API, which lists the PRs
+	// as issues
+	PullRequest *struct {
+		MergedAt *time.Time `json:"merged_at"`
+	} `json:"pull_request"`
+	Milestone *struct {
+		Number int `json:"number"`
+	} `json:"milestone"`
+	Labels []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+	User struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	CreatedAt time.Time `json:"created_at"`
+	RepoURL   string    `json:"-"` // Internal field, not from API
+	Files     []string  `json:"-"` // Changed file paths, only fetched when needed
+
+	// Details from the pulls API, only fetched when needed
+	Additions      int       `json:"-"`
+	Deletions      int       `json:"-"`
+	MergedAt       time.Time `json:"-"` // Zero when not merged
+	detailsFetched bool
+
+	FollowUps  []int         `json:"-"` // Numbers of the follow-up PRs collapsed into this one
+	Duplicates []PullRequest `json:"-"` // PRs with the same note merged into this one, with --dedup
+
+	ReleaseNote string `json:"-"` // Edited release note, used instead of the one in the body
+	CommitNote  bool   `json:"-"` // ReleaseNote comes from the merge commit trailer, with --commit-notes
+
+	Manual bool   `json:"-"` // Hand-written entry without a PR, from --manual-entries
+	Author string `json:"-"` // Author of a hand-written entry
+
+	NoteHistory *NoteHistory `json:"-"` // Only fetched when needed
+}
+
+// hasAnyLabel reports whether the PR has any of the given labels
+func hasAnyLabel(pr PullRequest, labels []string) bool {
+	for _, label := range pr.Labels {
+		for _, name := range labels {
+			if strings.EqualFold(label.Name, name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// prReleaseNote returns the release note of a PR as written, the edited one
+// when set or the one extracted from its description otherwise
+func prReleaseNote(pr PullRequest) string {
+	if pr.ReleaseNote != "" {
+		return pr.ReleaseNote
+	}
+	return extractReleaseNote(pr.Body)
+}
+
+// extractReleaseNote extracts the release note section from the PR
+// description
+func extractReleaseNote(body string) string {
+	releaseNote, _ := extract.ReleaseNote(body)
+	return releaseNote
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// printRateLimit implements --show-rate-limit, printing the quota left
+// according to the last GitHub API response
+func printRateLimit() {
+	status := apiClient.RateLimit()
+	if status == nil {
+		fmt.Println("Rate limit: no GitHub API requests made")
+		return
+	}
+
+	resource := status.Resource
+	if resource == "" {
+		resource = "core"
+	}
+	fmt.Printf("Rate limit: %s: %d/%d remaining, resets at %s\n", resource, status.Remaining, status.Limit, status.Reset.Format("15:04:05"))
+}
+
+// plannedWorkers returns how many of the requested parallel workers the
+// rate limit left affords, each task making about requestsPerTask requests,
+// so parallel runs don't exhaust the rate limit at once and then all wait for
+// it to reset. Without a known rate limit, the requested workers are used.
+func plannedWorkers(requested int, requestsPerTask int) int {
+	requested = max(requested, 1)
+
+	status := apiClient.RateLimit()
+	if status == nil || time.Now().After(status.Reset) {
+		return requested
+	}
+
+	affordable := status.Remaining / max(requestsPerTask, 1)
+	workers := min(max(affordable, 1), requested)
+	if workers < requested {
+		logger.Info("Running fewer parallel workers to stay within the rate limit", "workers", workers, "requested", requested, "remaining", status.Remaining, "resets_at", status.Reset.Format("15:04:05"))
+	}
+	return workers
+}
+
+// estimatedRequests returns the requests needed to list the PRs of the
+// milestones, one per release note label of each repository when they fit in
+// a page, or one per GitHub repository with --graphql
+func estimatedRequests(milestones []Milestone) int {
+	requests := 0
+	for _, milestone := range milestones {
+		if useGraphQL && isGitHubRepo(milestone.RepoURL) {
+			requests++
+			continue
+		}
+		requests += len(releaseNoteLabels(milestone.RepoURL))
+	}
+	return requests
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/jespino/github-mm-release-notes/github"
+)
+
+// rcTag returns the tag of a release candidate of a milestone, e.g. v9.8.0-rc2
+func rcTag(milestoneTitle string, rc int) string {
+	return fmt.Sprintf("%s-rc%d", milestoneTitle, rc)
+}
+
+// getTagDate returns the commit date of a tag, or a zero time if the
+// repository has no such tag
+func getTagDate(repoURL string, tag string) (time.Time, error) {
+	var commit struct {
+		Commit struct {
+			Committer struct {
+				Date time.Time `json:"date"`
+			} `json:"committer"`
+		} `json:"commit"`
+	}
+
+	err := apiClient.GetJSON(fmt.Sprintf("%s/commits/%s", repoURL, url.PathEscape(tag)), &commit)
+
+	var apiErr *github.APIError
+	if errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusNotFound || apiErr.StatusCode == http.StatusUnprocessableEntity) {
+		return time.Time{}, nil
+	} else if err != nil {
+		return time.Time{}, err
+	}
+
+	return commit.Commit.Committer.Date, nil
+}
+
+// filterPRsMergedSinceTag keeps the PRs merged after the tag was created in
+// their repository. All the PRs of a repository without the tag are kept.
+func filterPRsMergedSinceTag(prs []PullRequest, tag string) ([]PullRequest, error) {
+	if err := fetchPRDetails(prs); err != nil {
+		return nil, err
+	}
+
+	tagDates := make(map[string]time.Time)
+	var result []PullRequest
+	for _, pr := range prs {
+		tagDate, ok := tagDates[pr.RepoURL]
+		if !ok {
+			var err error
+			if tagDate, err = getTagDate(pr.RepoURL, tag); err != nil {
+				return nil, err
+			}
+			tagDates[pr.RepoURL] = tagDate
+		}
+
+		if tagDate.IsZero() || pr.MergedAt.After(tagDate) {
+			result = append(result, pr)
+		}
+	}
+
+	return result, nil
+}
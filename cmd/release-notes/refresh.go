@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// parsePRRef parses a PR reference in the owner/repo#123 form
+func parsePRRef(ref string) (string, int, error) {
+	repo, number, ok := strings.Cut(ref, "#")
+	n, err := strconv.Atoi(number)
+	if !ok || err != nil || n <= 0 || strings.Count(repo, "/") != 1 {
+		return "", 0, fmt.Errorf("invalid PR %q, must be in the owner/repo#123 form", ref)
+	}
+	return repo, n, nil
+}
+
+// getPullRequest returns a PR, through the issues API like the PRs of a
+// milestone
+func getPullRequest(repo string, number int) (PullRequest, error) {
+	var pr PullRequest
+	if err := apiClient.GetJSON(fmt.Sprintf("%s/issues/%d", repoURLFromName(repo), number), &pr); err != nil {
+		return pr, fmt.Errorf("error getting %s#%d: %w", repo, number, err)
+	}
+	pr.RepoURL = repoURLFromName(repo)
+
+	return pr, nil
+}
+
+// runRefresh implements the refresh command, which fetches the given PRs
+// again and updates their entries in a release set (--release-set) or a
+// release candidate snapshot (--milestone and --rc), without extracting the
+// whole milestone again
+func runRefresh() error {
+	if len(prRefs) == 0 {
+		return fmt.Errorf("the --pr flag with the PRs to refresh is required")
+	}
+
+	prs := make(map[string]PullRequest)
+	for _, ref := range prRefs {
+		repo, number, err := parsePRRef(ref)
+		if err != nil {
+			return err
+		}
+		pr, err := getPullRequest(repo, number)
+		if err != nil {
+			return err
+		}
+		prs[fmt.Sprintf("%s#%d", repo, number)] = pr
+	}
+
+	switch {
+	case releaseSetFile != "":
+		return refreshReleaseSet(releaseSetFile, prs)
+	case milestoneFlag != "" && rcNumber > 0:
+		return refreshRCSnapshot(milestoneFlag, rcNumber, prs)
+	default:
+		return fmt.Errorf("either the --release-set flag or the --milestone and --rc flags are required")
+	}
+}
+
+// refreshReleaseSet updates the entries of the release set for the PRs
+func refreshReleaseSet(path string, prs map[string]PullRequest) error {
+	set, _, err := loadReleaseSet(path)
+	if err != nil {
+		return err
+	}
+
+	refreshed := 0
+	for i, entry := range set.Entries {
+		pr, ok := prs[fmt.Sprintf("%s#%d", entry.Repo, entry.Number)]
+		if !ok {
+			continue
+		}
+
+		// Keep the details only fetched on demand, the PR was not rebuilt
+		updated := buildReleaseSet([]PullRequest{pr}, set.Milestone, set.Title, set.RepoName).Entries[0]
+		updated.FollowUps = entry.FollowUps
+		updated.Duplicates = entry.Duplicates
+		updated.Excluded = entry.Excluded
+		updated.Comments = entry.Comments
+		updated.Files = entry.Files
+		updated.Additions = entry.Additions
+		updated.Deletions = entry.Deletions
+		set.Entries[i] = updated
+		refreshed++
+		fmt.Printf("Refreshed %s#%d: %s\n", entry.Repo, entry.Number, updated.Note)
+	}
+
+	if err := checkAllRefreshed(refreshed, prs); err != nil {
+		return err
+	}
+
+	return writeReleaseSet(path, set)
+}
+
+// refreshRCSnapshot updates the entries of a release candidate snapshot for
+// the PRs
+func refreshRCSnapshot(milestoneTitle string, rc int, prs map[string]PullRequest) error {
+	dir, err := snapshotsDir()
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(dir, snapshotFileName(milestoneTitle, rc))
+
+	snapshot, _, err := loadRCSnapshot(path)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	refreshed := 0
+	for i, entry := range snapshot.Entries {
+		pr, ok := prs[fmt.Sprintf("%s#%d", entry.Repo, entry.Number)]
+		if !ok {
+			continue
+		}
+
+		provenance := newProvenance(pr, now)
+		snapshot.Entries[i] = SnapshotEntry{
+			Repo:       entry.Repo,
+			Number:     entry.Number,
+			Title:      pr.Title,
+			Note:       releaseNoteForPR(pr),
+			RCFix:      rcFixRegexp.MatchString(pr.Body),
+			Provenance: &provenance,
+		}
+		refreshed++
+		fmt.Printf("Refreshed %s#%d: %s\n", entry.Repo, entry.Number, snapshot.Entries[i].Note)
+	}
+
+	if err := checkAllRefreshed(refreshed, prs); err != nil {
+		return err
+	}
+
+	return writeRCSnapshot(path, snapshot)
+}
+
+// checkAllRefreshed fails when some of the PRs to refresh had no entry, as
+// adding entries would bypass the milestone and label rules
+func checkAllRefreshed(refreshed int, prs map[string]PullRequest) error {
+	if refreshed < len(prs) {
+		return fmt.Errorf("%d of the PRs are not in the stored notes, extract the milestone again to add them", len(prs)-refreshed)
+	}
+	return nil
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/jespino/github-mm-release-notes/extract"
+	"github.com/jespino/github-mm-release-notes/github"
+)
+
+// toolRepo is the repository of this tool, released with the release command
+const toolRepo = "jespino/github-mm-release-notes"
+
+// releaseNotesFileName and releaseMetadataFileName are the files written by
+// the release command
+const (
+	releaseNotesFileName    = "release-notes.md"
+	releaseMetadataFileName = "metadata.json"
+)
+
+// ReleaseMetadata describes a version of the tool, with the same fields as
+// the dist/metadata.json file of goreleaser
+type ReleaseMetadata struct {
+	ProjectName string `json:"project_name"`
+	Tag         string `json:"tag"`
+	PreviousTag string `json:"previous_tag"`
+	Version     string `json:"version"`
+	Commit      string `json:"commit"`
+	Date        string `json:"date"`
+	Runtime     struct {
+		Goos   string `json:"goos"`
+		Goarch string `json:"goarch"`
+	} `json:"runtime"`
+}
+
+// getCommit returns the SHA and date of the commit a ref points to, or an
+// empty SHA if the repository has no such ref
+func getCommit(repoURL string, ref string) (string, time.Time, error) {
+	var commit struct {
+		SHA    string `json:"sha"`
+		Commit struct {
+			Committer struct {
+				Date time.Time `json:"date"`
+			} `json:"committer"`
+		} `json:"commit"`
+	}
+
+	err := apiClient.GetJSON(fmt.Sprintf("%s/commits/%s", repoURL, url.PathEscape(ref)), &commit)
+
+	var apiErr *github.APIError
+	if errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusNotFound || apiErr.StatusCode == http.StatusUnprocessableEntity) {
+		return "", time.Time{}, nil
+	} else if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return commit.SHA, commit.Commit.Committer.Date, nil
+}
+
+// getLatestReleaseTag returns the tag of the latest published release of a
+// repository, or an empty string if there is none
+func getLatestReleaseTag(repoURL string) (string, error) {
+	var release Release
+	err := apiClient.GetJSON(repoURL+"/releases/latest", &release)
+
+	var apiErr *github.APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+
+	return release.TagName, nil
+}
+
+// getPRsMergedSince returns the PRs of a repository merged after a time,
+// looking at the 100 most recently updated closed PRs
+func getPRsMergedSince(repoURL string, since time.Time) ([]PullRequest, error) {
+	var pulls []struct {
+		PullRequest
+		MergedAt *time.Time `json:"merged_at"`
+	}
+	if err := apiClient.GetJSON(repoURL+"/pulls?state=closed&sort=updated&direction=desc&per_page=100", &pulls); err != nil {
+		return nil, err
+	}
+
+	var prs []PullRequest
+	for _, pull := range pulls {
+		if pull.MergedAt == nil || !pull.MergedAt.After(since) {
+			continue
+		}
+
+		pr := pull.PullRequest
+		pr.RepoURL = repoURL
+		pr.MergedAt = *pull.MergedAt
+		prs = append(prs, pr)
+	}
+
+	return prs, nil
+}
+
+// runRelease implements the release command, which prepares a release of
+// this tool: it extracts the release notes of the PRs merged since the
+// previous release, like for Mattermost, and writes them along with a
+// goreleaser-compatible metadata.json for packaging
+func runRelease() error {
+	if publishTag == "" {
+		return fmt.Errorf("the --github-release flag with the tag to release is required")
+	}
+
+	repoURL := repoURLFromName(toolRepo)
+
+	previousTag := sinceTag
+	if previousTag == "" {
+		var err error
+		if previousTag, err = getLatestReleaseTag(repoURL); err != nil {
+			return fmt.Errorf("error getting the latest release: %w", err)
+		}
+	}
+
+	var since time.Time
+	if previousTag != "" {
+		var err error
+		if _, since, err = getCommit(repoURL, previousTag); err != nil {
+			return fmt.Errorf("error getting tag %s: %w", previousTag, err)
+		}
+	}
+
+	// The tag may not be pushed yet when preparing the release
+	commit, date, err := getCommit(repoURL, publishTag)
+	if err == nil && commit == "" {
+		commit, date, err = getCommit(repoURL, "HEAD")
+	}
+	if err != nil {
+		return fmt.Errorf("error getting the release commit: %w", err)
+	}
+
+	prs, err := getPRsMergedSince(repoURL, since)
+	if err != nil {
+		return fmt.Errorf("error getting merged PRs: %w", err)
+	}
+
+	var notes strings.Builder
+	notes.WriteString("## What's Changed\n\n")
+	skipped := 0
+	for _, pr := range prs {
+		releaseNote := releaseNoteForPR(pr)
+		if !extract.HasReleaseNote(releaseNote) {
+			skipped++
+			continue
+		}
+		fmt.Fprintf(&notes, "- %s (#%d)\n", releaseNote, pr.Number)
+	}
+	if previousTag != "" {
+		fmt.Fprintf(&notes, "\n**Full Changelog**: https://github.com/%s/compare/%s...%s\n", toolRepo, previousTag, publishTag)
+	}
+
+	metadata := ReleaseMetadata{
+		ProjectName: filepath.Base(toolRepo),
+		Tag:         publishTag,
+		PreviousTag: previousTag,
+		Version:     strings.TrimPrefix(publishTag, "v"),
+		Commit:      commit,
+		Date:        date.UTC().Format(time.RFC3339),
+	}
+	metadata.Runtime.Goos = runtime.GOOS
+	metadata.Runtime.Goarch = runtime.GOARCH
+
+	metadataJSON, err := json.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(distDir, 0o755); err != nil {
+		return err
+	}
+	notesPath := filepath.Join(distDir, releaseNotesFileName)
+	if err := os.WriteFile(notesPath, []byte(notes.String()), 0o644); err != nil {
+		return err
+	}
+	metadataPath := filepath.Join(distDir, releaseMetadataFileName)
+	if err := os.WriteFile(metadataPath, append(metadataJSON, '\n'), 0o644); err != nil {
+		return err
+	}
+
+	sinceDescription := "the first commit"
+	if previousTag != "" {
+		sinceDescription = previousTag
+	}
+	fmt.Printf("Wrote the notes of %d PRs merged since %s to %s (%d without release notes skipped)\n", len(prs)-skipped, sinceDescription, notesPath, skipped)
+	fmt.Printf("Wrote the release metadata to %s\n", metadataPath)
+
+	return nil
+}
@@ -0,0 +1,264 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ReleaseSet is the intermediate representation of the extracted release
+// notes of a milestone. It is written as annotated YAML that can be edited by
+// hand and rendered again into any format with the render command, always
+// producing the same output for the same file.
+type ReleaseSet struct {
+	// SchemaVersion is the version of the format, see releaseSetSchema
+	SchemaVersion int `yaml:"schema_version"`
+	// Milestone is the title of the milestone the notes were extracted from
+	Milestone string `yaml:"milestone"`
+	// Title is the title used in the rendered notes, e.g. with the release
+	// candidate number
+	Title string `yaml:"title"`
+	// RepoName is the repository, or "all repositories", and selects the
+	// changelog type (mattermost, mobile or desktop) used by --claude
+	RepoName string `yaml:"repo_name"`
+	// GeneratedAt is when the notes were extracted
+	GeneratedAt time.Time `yaml:"generated_at"`
+	// Entries are the changes, in rendering order
+	Entries []ReleaseEntry `yaml:"entries"`
+}
+
+// ReleaseEntry is a change of a release set, extracted from a PR
+type ReleaseEntry struct {
+	Repo   string `yaml:"repo"`
+	Number int    `yaml:"number"`
+	Title  string `yaml:"title"`
+	// Note is the release note, edit it to change the rendered notes
+	Note string `yaml:"note"`
+	// Categories are the labels of the PR, which select the sections and
+	// highlights the entry is listed in
+	Categories []string `yaml:"categories,omitempty"`
+	// FollowUps are the follow-up PRs listed along with this one
+	FollowUps []int `yaml:"follow_ups,omitempty"`
+	// Duplicates are the PRs with the same note, in the owner/repo#123
+	// form, merged into this one with --dedup
+	Duplicates []string `yaml:"duplicates,omitempty"`
+	// Files, Additions and Deletions are only set when they were fetched,
+	// for the per-area notes and the impact hints
+	Files     []string `yaml:"files,omitempty"`
+	Additions int      `yaml:"additions,omitempty"`
+	Deletions int      `yaml:"deletions,omitempty"`
+	// Body is the PR description, holding the test steps and images used by
+	// the qa and announcement formats
+	Body string `yaml:"body,omitempty"`
+	// Provenance records where the note came from, for audits
+	Provenance *Provenance `yaml:"provenance,omitempty"`
+	// Excluded is set for the entries left out of the notes with the exclude
+	// command
+	Excluded *Exclusion `yaml:"excluded,omitempty"`
+	// Comments are the review comments of the editors, added with the
+	// annotate command, never rendered
+	Comments []Comment `yaml:"comments,omitempty"`
+}
+
+// releaseSetHeader documents the YAML file for the people editing it
+const releaseSetHeader = `Release notes extracted by github-mm-release-notes. Edit the "note" of
+the entries, reorder or remove them, then render the notes again with:
+  release-notes render --release-set=<this file> [--format=...]`
+
+// buildReleaseSet returns the release set of the PRs of a milestone
+func buildReleaseSet(prs []PullRequest, milestoneTitle string, notesTitle string, repoName string) ReleaseSet {
+	set := ReleaseSet{Milestone: milestoneTitle, Title: notesTitle, RepoName: repoName, GeneratedAt: time.Now().UTC()}
+	for _, pr := range prs {
+		provenance := newProvenance(pr, set.GeneratedAt)
+		entry := ReleaseEntry{
+			Repo:       repoNameFromURL(pr.RepoURL),
+			Number:     pr.Number,
+			Title:      pr.Title,
+			Note:       prReleaseNote(pr),
+			FollowUps:  pr.FollowUps,
+			Files:      pr.Files,
+			Additions:  pr.Additions,
+			Deletions:  pr.Deletions,
+			Body:       pr.Body,
+			Provenance: &provenance,
+		}
+		for _, label := range pr.Labels {
+			entry.Categories = append(entry.Categories, label.Name)
+		}
+		for _, duplicate := range pr.Duplicates {
+			entry.Duplicates = append(entry.Duplicates, prKey(duplicate))
+		}
+		set.Entries = append(set.Entries, entry)
+	}
+
+	return set
+}
+
+// pullRequests returns the PRs the renderers consume for the entries of the
+// release set, with the notes of the entries instead of the ones in the PR
+// descriptions
+func (s ReleaseSet) pullRequests() []PullRequest {
+	prs := make([]PullRequest, 0, len(s.Entries))
+	for _, entry := range s.Entries {
+		if entry.Excluded != nil {
+			continue
+		}
+		pr := PullRequest{
+			Number:      entry.Number,
+			Title:       entry.Title,
+			Body:        entry.Body,
+			RepoURL:     repoURLFromName(entry.Repo),
+			ReleaseNote: entry.Note,
+			FollowUps:   entry.FollowUps,
+			Files:       entry.Files,
+			Additions:   entry.Additions,
+			Deletions:   entry.Deletions,
+			// Never query GitHub when rendering a release set
+			detailsFetched: true,
+		}
+		if entry.Provenance != nil && entry.Provenance.Extractor == extractorManual {
+			pr.Manual = true
+			pr.Author = entry.Provenance.Author
+		}
+		if pr.Files == nil {
+			pr.Files = []string{}
+		}
+		for _, ref := range entry.Duplicates {
+			if repo, number, err := parsePRRef(ref); err == nil {
+				pr.Duplicates = append(pr.Duplicates, PullRequest{Number: number, RepoURL: repoURLFromName(repo)})
+			}
+		}
+		for _, category := range entry.Categories {
+			pr.Labels = append(pr.Labels, struct {
+				Name string `json:"name"`
+			}{Name: category})
+		}
+		prs = append(prs, pr)
+	}
+
+	return prs
+}
+
+// writeReleaseSet writes the release set as YAML, annotated with the
+// documentation of the format and the link of the PR of every entry
+func writeReleaseSet(path string, set ReleaseSet) error {
+	set.SchemaVersion = releaseSetSchema.Version
+
+	var doc yaml.Node
+	if err := doc.Encode(set); err != nil {
+		return err
+	}
+	doc.HeadComment = releaseSetHeader
+
+	// The mapping alternates keys and values
+	for i := 0; i+1 < len(doc.Content); i += 2 {
+		if doc.Content[i].Value != "entries" {
+			continue
+		}
+		for j, entry := range doc.Content[i+1].Content {
+			if provenance := set.Entries[j].Provenance; provenance != nil && provenance.Extractor == extractorManual {
+				entry.HeadComment = "Manual entry"
+				continue
+			}
+			entry.HeadComment = fmt.Sprintf("https://github.com/%s/pull/%d", set.Entries[j].Repo, set.Entries[j].Number)
+			if set.Entries[j].Excluded != nil {
+				entry.HeadComment += "\nExcluded from the notes, bring it back with the restore command"
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return err
+	}
+	if err := encoder.Close(); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, buf.Bytes(), 0644)
+}
+
+// loadReleaseSet reads a release set written by writeReleaseSet, migrating
+// it to the current schema, and reports whether it was migrated
+func loadReleaseSet(path string) (ReleaseSet, bool, error) {
+	var set ReleaseSet
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return set, false, err
+	}
+
+	migrated, err := releaseSetSchema.decodeYAML(data, &set)
+	if err != nil {
+		return set, false, fmt.Errorf("invalid release set %s: %w", path, err)
+	}
+
+	return set, migrated, nil
+}
+
+// runRender implements the render command, which renders the release notes
+// of a release set file in the requested format, without GitHub access
+func runRender() error {
+	if releaseSetFile == "" {
+		return fmt.Errorf("the --release-set flag with the release set file is required")
+	}
+
+	set, _, err := loadReleaseSet(releaseSetFile)
+	if err != nil {
+		return err
+	}
+	if fixTerms {
+		for i := range set.Entries {
+			set.Entries[i].Note = fixTerminology(set.Entries[i].Note)
+		}
+	}
+	// Notes edited to NONE drop their entries
+	prs, noneNotes := separateNoneNotes(filterPRsBySKU(set.pullRequests(), skuFilter))
+	if anonymizeOutput {
+		if err := anonymizePRs(prs); err != nil {
+			return err
+		}
+	}
+	if err := applyPinnedOrder(prs); err != nil {
+		return err
+	}
+
+	if checklistFile != "" {
+		if err := writeChecklist(checklistFile, prs, set.Title); err != nil {
+			return fmt.Errorf("error writing QA checklist: %w", err)
+		}
+		logger.Info("QA checklist written", "file", checklistFile)
+	}
+
+	if galleryFile != "" {
+		if err := writeGallery(galleryFile, prs, set.Title); err != nil {
+			return fmt.Errorf("error writing gallery: %w", err)
+		}
+		logger.Info("Gallery written", "file", galleryFile)
+	}
+
+	if outputFile != "" && !splitDelivery {
+		restore, err := redirectOutput(outputFile)
+		if err != nil {
+			return err
+		}
+		defer restore()
+	}
+
+	if splitDelivery {
+		if err := printDeliveryVariants(prs, set.Milestone, changeLogTypeFor(set.RepoName)); err != nil {
+			return err
+		}
+	} else if err := printReleaseNotesByArea(prs, set.Title, changeLogTypeFor(set.RepoName)); err != nil {
+		return err
+	}
+	printNoneNotes(noneNotes)
+	printExcludedEntries(set)
+
+	return nil
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jespino/github-mm-release-notes/extract"
+	"github.com/jespino/github-mm-release-notes/internal/format"
+)
+
+// printReleaseNotesByArea prints the release notes of the given PRs, split
+// into per-area sub-changelogs when requested
+func printReleaseNotesByArea(prs []PullRequest, milestoneTitle string, changeLogType string) error {
+	if !useAreas {
+		return printReleaseNotes(prs, milestoneTitle, changeLogType)
+	}
+
+	// Attribute monorepo PRs to areas by their changed paths
+	if err := fetchPRFiles(prs); err != nil {
+		return fmt.Errorf("Error getting changed files: %v", err)
+	}
+
+	for _, group := range groupPRsByArea(prs) {
+		fmt.Printf("## %s\n\n", group.Area)
+		if err := printReleaseNotes(group.PRs, milestoneTitle+" ("+group.Area+")", changeLogType); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// printReleaseNotes prints the release notes of the given PRs, formatted by
+// Claude when requested
+func printReleaseNotes(prs []PullRequest, milestoneTitle string, changeLogType string) error {
+	if useClaudeFormat {
+		// Build input for Claude AI
+		var releaseNotesBuffer bytes.Buffer
+		for _, pr := range prs {
+			releaseNote := releaseNoteForPR(pr)
+			releaseNotesBuffer.WriteString(fmt.Sprintf("%s: %s\n", prLabel(pr), pr.Title))
+			releaseNotesBuffer.WriteString(fmt.Sprintf("%s\n\n", releaseNote))
+		}
+
+		// Send to Claude API for formatting
+		formattedNotes, err := formatReleaseNotesWithClaude(claudeToken, releaseNotesBuffer.String(), milestoneTitle, changeLogType)
+		if err != nil {
+			return fmt.Errorf("Error using Claude to format release notes: %v", err)
+		}
+
+		// Print the formatted notes
+		fmt.Println(formattedNotes)
+		return nil
+	}
+
+	if outputFormat == formatAnnouncement {
+		return renderAnnouncement(prs, milestoneTitle)
+	}
+
+	// Guessing the type of change by changed paths needs the files
+	if (groupByType || showStats || outputFormat == formatMarkdown || outputFormat == formatHTML || templateFile != "") && categoryRulesUsePaths() {
+		if err := fetchPRFiles(prs); err != nil {
+			return fmt.Errorf("Error getting changed files: %v", err)
+		}
+	}
+
+	if templateFile != "" {
+		return renderTemplate(prs, milestoneTitle)
+	}
+
+	if outputFormat == formatMarkdown {
+		renderMarkdown(prs)
+		return nil
+	}
+
+	if outputFormat == formatHTML {
+		renderHTML(prs, milestoneTitle)
+		return nil
+	}
+
+	// Standard output format
+	format.WriteTextHeader(os.Stdout, milestoneTitle)
+	if groupByType {
+		printGuessRate(prs)
+		for _, group := range groupPRsByType(prs) {
+			format.WriteTextSection(os.Stdout, group.Type)
+			for _, pr := range group.PRs {
+				format.WriteTextEntry(os.Stdout, textEntry(pr))
+			}
+			printStats(prsStats(group.PRs))
+		}
+		return nil
+	}
+
+	for _, pr := range prs {
+		format.WriteTextEntry(os.Stdout, textEntry(pr))
+	}
+	printSectionStats(prs)
+
+	return nil
+}
+
+// textEntry returns a PR and its release note as listed in the standard
+// output format
+func textEntry(pr PullRequest) format.TextEntry {
+	entry := format.TextEntry{Label: prLabel(pr), Title: pr.Title, Note: releaseNoteForPR(pr)}
+	if groupByType {
+		entry.Title += provisionalMarker(pr, false)
+	}
+	if pr.NoteHistory != nil {
+		entry.History = formatNoteHistory(*pr.NoteHistory, reviewCutoffTime)
+	}
+
+	// Extended report for QA
+	if outputFormat == formatQA {
+		entry.TestSteps = extract.TestPlan(pr.Body, splitList(testHeadings))
+		if entry.TestSteps == "" {
+			entry.TestSteps = "No test steps found"
+		}
+	}
+	return entry
+}
+
+// releaseNoteForPR returns the release note of a PR with the annotations
+// requested by the flags
+func releaseNoteForPR(pr PullRequest) string {
+	releaseNote := prReleaseNote(pr)
+	if !extract.HasReleaseNote(releaseNote) {
+		return releaseNote
+	}
+
+	releaseNote = applyNoteTemplate(pr, releaseNote)
+
+	if shortLinks {
+		releaseNote = shortenLinks(releaseNote)
+	} else if resolveRefLinks {
+		releaseNote = resolveRefs(releaseNote, outputFormat == formatMarkdown || outputFormat == formatAnnouncement)
+	}
+
+	if showFlags {
+		if annotation := featureFlagAnnotation(pr); annotation != "" {
+			releaseNote += " " + annotation
+		}
+	}
+
+	return releaseNote
+}
+
+// prLabel returns the "PR #123" prefix used when listing a PR, including its
+// follow-ups and the impact hint when requested
+func prLabel(pr PullRequest) string {
+	if pr.Manual {
+		return "Manual entry"
+	}
+	label := fmt.Sprintf("PR #%d", pr.Number)
+	var others []string
+	for _, number := range pr.FollowUps {
+		others = append(others, fmt.Sprintf("#%d", number))
+	}
+	for _, duplicate := range pr.Duplicates {
+		others = append(others, prRef(duplicate))
+	}
+	if len(others) > 0 {
+		label += " (+ " + strings.Join(others, ", ") + ")"
+	}
+	if showImpact {
+		label += fmt.Sprintf(" [%s]", impactHint(pr))
+	}
+	return label
+}
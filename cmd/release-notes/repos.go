@@ -0,0 +1,288 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Repository is a repository the release notes are extracted from
+type Repository struct {
+	// Name is the owner/repo name, or the group/project path on GitLab
+	Name string `yaml:"name"`
+	// Forge is where the repository is hosted: github (default), gitlab or
+	// gitea, which also covers Forgejo
+	Forge string `yaml:"forge"`
+	// BaseURL is the URL of a self-hosted instance, required for Gitea and
+	// defaulting to https://gitlab.com for GitLab
+	BaseURL string `yaml:"base_url"`
+	// DisplayName is shown in the repository menu, defaults to the name
+	DisplayName string `yaml:"display_name"`
+	// Labels mark the PRs with release notes, a PR with any of them is
+	// included. Defaults to release-note.
+	Labels []string `yaml:"labels"`
+	// ChangelogType is the kind of changelog Claude is asked to write with
+	// --claude: mattermost (default), mobile or desktop
+	ChangelogType string `yaml:"changelog_type"`
+}
+
+// URL returns the API URL of the repository
+func (r Repository) URL() string {
+	return r.forge().APIURL(r)
+}
+
+// defaultRepositories are the repositories used when the configuration
+// doesn't define any
+var defaultRepositories = []Repository{
+	{Name: "mattermost/mattermost"},
+	{Name: "mattermost/enterprise"},
+	{Name: "mattermost/mattermost-mobile", ChangelogType: "mobile"},
+	{Name: "mattermost/desktop", ChangelogType: "desktop"},
+}
+
+// findRepository returns the configured repository with the given API URL,
+// or one with the defaults if it is not configured
+func findRepository(repoURL string) Repository {
+	for _, repo := range config.Repositories {
+		if repo.URL() == repoURL {
+			return repo
+		}
+	}
+	return Repository{Name: repoNameFromURL(repoURL)}
+}
+
+// releaseNoteLabels returns the labels marking the PRs with release notes in
+// a repository
+func releaseNoteLabels(repoURL string) []string {
+	if labels := findRepository(repoURL).Labels; len(labels) > 0 {
+		return labels
+	}
+	return []string{"release-note"}
+}
+
+// RepoOption is an option of the repository menu, selecting one or several
+// repositories
+type RepoOption struct {
+	// Name is shown in the menu and the output, and selects the option with
+	// the --repo flag
+	Name  string
+	Repos []Repository
+	// Group is the name of the repository group of the configuration the
+	// option selects, if any
+	Group string
+}
+
+// FlagValue is the value of the --repo flag selecting the option
+func (o RepoOption) FlagValue() string {
+	if o.Group != "" {
+		return o.Group
+	}
+	if len(o.Repos) == len(config.Repositories) && len(o.Repos) > 1 {
+		return "all"
+	}
+
+	var names []string
+	for _, repo := range o.Repos {
+		names = append(names, repo.Name)
+	}
+	return strings.Join(names, "+")
+}
+
+// repoOptions returns the options of the repository menu: every repository,
+// mattermost/mattermost along with mattermost/enterprise when both are
+// configured, the repository groups of the configuration, and all the
+// repositories together
+func repoOptions() []RepoOption {
+	var options []RepoOption
+	byName := make(map[string]Repository)
+	for _, repo := range config.Repositories {
+		name := repo.DisplayName
+		if name == "" {
+			name = repo.Name
+		}
+		options = append(options, RepoOption{Name: name, Repos: []Repository{repo}})
+		byName[repo.Name] = repo
+	}
+
+	server, hasServer := byName["mattermost/mattermost"]
+	enterprise, hasEnterprise := byName["mattermost/enterprise"]
+	if hasServer && hasEnterprise && len(config.Repositories) > 2 {
+		options = append(options, RepoOption{Name: "mattermost/mattermost + mattermost/enterprise", Repos: []Repository{server, enterprise}})
+	}
+
+	groups := make([]string, 0, len(config.RepoGroups))
+	for group := range config.RepoGroups {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+	for _, group := range groups {
+		// The groups are checked when loading the configuration
+		repos, _ := groupRepositories(config.RepoGroups[group], config.Repositories)
+		var names []string
+		for _, repo := range repos {
+			names = append(names, repo.Name)
+		}
+		options = append(options, RepoOption{Name: fmt.Sprintf("%s (%s)", group, strings.Join(names, " + ")), Repos: repos, Group: group})
+	}
+
+	if len(config.Repositories) > 1 {
+		options = append(options, RepoOption{Name: "all repositories", Repos: config.Repositories})
+	}
+
+	return options
+}
+
+// findRepoOption returns the option of the repository menu selected by the
+// value of the --repo flag
+func findRepoOption(options []RepoOption, value string) (RepoOption, bool) {
+	for _, option := range options {
+		if strings.EqualFold(option.FlagValue(), value) {
+			return option, true
+		}
+	}
+	return RepoOption{}, false
+}
+
+// repoFlagValues returns the values accepted by the --repo flag
+func repoFlagValues(options []RepoOption) []string {
+	var values []string
+	for _, option := range options {
+		values = append(values, option.FlagValue())
+	}
+	return values
+}
+
+// repositoryURLs returns the API URLs of the repositories
+func repositoryURLs(repos []Repository) []string {
+	var urls []string
+	for _, repo := range repos {
+		urls = append(urls, repo.URL())
+	}
+	return urls
+}
+
+// validateRepositories checks the configured repositories
+func validateRepositories(repos []Repository) error {
+	for _, repo := range repos {
+		if repo.Forge != "" && !containsString(forges, repo.Forge) {
+			return fmt.Errorf("invalid forge %q of repository %s, must be one of: %s", repo.Forge, repo.Name, strings.Join(forges, ", "))
+		}
+		if repo.BaseURL != "" && repo.Forge != forgeGitLab && repo.Forge != forgeGitea {
+			return fmt.Errorf("repository %s has a base URL, only supported for the gitlab and gitea forges", repo.Name)
+		}
+		if repo.BaseURL == "" && repo.Forge == forgeGitea {
+			return fmt.Errorf("repository %s has no base URL, required for the gitea forge", repo.Name)
+		}
+
+		// GitLab projects can be in nested groups
+		if repo.Forge == forgeGitLab {
+			if !strings.Contains(repo.Name, "/") {
+				return fmt.Errorf("invalid repository name %q, must be group/project", repo.Name)
+			}
+		} else if strings.Count(repo.Name, "/") != 1 {
+			return fmt.Errorf("invalid repository name %q, must be owner/repo", repo.Name)
+		}
+	}
+	return nil
+}
+
+// groupRepositories returns the repositories of a group, given by their
+// owner/repo name, their name without the owner, e.g. mattermost-mobile, or
+// their display name
+func groupRepositories(names []string, repos []Repository) ([]Repository, error) {
+	var members []Repository
+	for _, name := range names {
+		found := false
+		for _, repo := range repos {
+			if strings.EqualFold(repo.Name, name) || strings.EqualFold(path.Base(repo.Name), name) || (repo.DisplayName != "" && strings.EqualFold(repo.DisplayName, name)) {
+				members = append(members, repo)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown repository %q", name)
+		}
+	}
+	return members, nil
+}
+
+// validateRepoGroups checks that the repository groups have a name that
+// can't be confused with a --repo value and only list configured
+// repositories
+func validateRepoGroups(groups map[string][]string, repos []Repository) error {
+	for group, names := range groups {
+		if group == "" || group == "all" || strings.ContainsAny(group, "/+") {
+			return fmt.Errorf("invalid repository group name %q, must not be all nor contain / or +", group)
+		}
+		if len(names) == 0 {
+			return fmt.Errorf("repository group %s has no repositories", group)
+		}
+		if _, err := groupRepositories(names, repos); err != nil {
+			return fmt.Errorf("repository group %s: %w", group, err)
+		}
+	}
+	return nil
+}
+
+// MilestoneRule excludes repositories from the notes of the milestones whose
+// title matches any of its patterns, e.g. the desktop app from the notes of
+// the v10.* server milestones
+type MilestoneRule struct {
+	// Milestones are glob patterns of milestone titles, e.g. "v10.*"
+	Milestones   []string `yaml:"milestones"`
+	ExcludeRepos []string `yaml:"exclude_repos"`
+}
+
+// matches reports whether the rule applies to a milestone
+func (r MilestoneRule) matches(milestoneTitle string) bool {
+	for _, pattern := range r.Milestones {
+		if ok, _ := path.Match(pattern, milestoneTitle); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// isRepoExcluded reports whether a rule of the configuration excludes the
+// repository from the notes of a milestone
+func isRepoExcluded(repoURL string, milestoneTitle string) bool {
+	for _, rule := range config.MilestoneRules {
+		if !rule.matches(milestoneTitle) {
+			continue
+		}
+		for _, repo := range rule.ExcludeRepos {
+			if repoURLFromName(repo) == repoURL {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// excludeMilestones leaves out the milestones of the repositories excluded
+// from their notes by the milestone rules of the configuration
+func excludeMilestones(milestones []Milestone) []Milestone {
+	var kept []Milestone
+	for _, milestone := range milestones {
+		if isRepoExcluded(milestone.RepoURL, milestone.Title) {
+			logger.Info("Skipping repository excluded from the milestone by the config", "repo", repoNameFromURL(milestone.RepoURL), "milestone", milestone.Title)
+			continue
+		}
+		kept = append(kept, milestone)
+	}
+	return kept
+}
+
+// validateMilestoneRules checks the patterns of the milestone rules
+func validateMilestoneRules(rules []MilestoneRule) error {
+	for _, rule := range rules {
+		for _, pattern := range rule.Milestones {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("invalid milestone pattern %q: %w", pattern, err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// RevertedChange pairs a revert PR with the PR it reverts
+type RevertedChange struct {
+	Revert   PullRequest
+	Original *PullRequest // nil when the original PR is not in the milestone
+}
+
+var (
+	// revertTitleRegexp matches the titles GitHub generates for reverts, e.g. `Revert "Add feature"`
+	revertTitleRegexp = regexp.MustCompile(`^Revert "(.*)"$`)
+	// revertBodyRegexp matches the body GitHub generates for reverts, e.g. "Reverts mattermost/mattermost#123"
+	revertBodyRegexp = regexp.MustCompile(`(?m)^Reverts ([\w.-]+/[\w.-]+)?#(\d+)`)
+)
+
+// revertDepth returns how many times a title is wrapped in `Revert "…"`
+func revertDepth(title string) int {
+	depth := 0
+	for {
+		matches := revertTitleRegexp.FindStringSubmatch(strings.TrimSpace(title))
+		if matches == nil {
+			return depth
+		}
+		title = matches[1]
+		depth++
+	}
+}
+
+// separateReverts removes the revert PRs and the PRs they revert from the
+// list, so cancelled changes never make it into the notes, and returns them
+// paired for the "reverted changes" appendix. A revert of a revert cancels
+// out the first revert, leaving the re-landed original in the notes.
+func separateReverts(prs []PullRequest) ([]PullRequest, []RevertedChange) {
+	var reverts []int
+	for i, pr := range prs {
+		if revertDepth(pr.Title) > 0 {
+			reverts = append(reverts, i)
+		}
+	}
+
+	// Resolve the outermost reverts first, so a revert of a revert pairs
+	// with the first revert before that one gets to cancel the original
+	sort.SliceStable(reverts, func(i, j int) bool {
+		return revertDepth(prs[reverts[i]].Title) > revertDepth(prs[reverts[j]].Title)
+	})
+
+	excluded := make(map[int]bool)
+	var changes []RevertedChange
+	for _, i := range reverts {
+		if excluded[i] {
+			continue
+		}
+		excluded[i] = true
+
+		change := RevertedChange{Revert: prs[i]}
+		if j := findRevertedPR(prs, prs[i]); j >= 0 && !excluded[j] {
+			excluded[j] = true
+			change.Original = &prs[j]
+		}
+		changes = append(changes, change)
+	}
+
+	var kept []PullRequest
+	for i, pr := range prs {
+		if !excluded[i] {
+			kept = append(kept, pr)
+		}
+	}
+
+	return kept, changes
+}
+
+// findRevertedPR returns the index of the PR reverted by revert, looking
+// first for the "Reverts owner/repo#123" line and then for the title. It
+// returns -1 if the original PR is not in the list.
+func findRevertedPR(prs []PullRequest, revert PullRequest) int {
+	if matches := revertBodyRegexp.FindStringSubmatch(revert.Body); matches != nil {
+		number, _ := strconv.Atoi(matches[2])
+		for i, pr := range prs {
+			if pr.Number == number && pr.RepoURL == revert.RepoURL &&
+				(matches[1] == "" || strings.EqualFold(matches[1], repoNameFromURL(pr.RepoURL))) {
+				return i
+			}
+		}
+	}
+
+	title := revertTitleRegexp.FindStringSubmatch(strings.TrimSpace(revert.Title))[1]
+	for i, pr := range prs {
+		if pr.RepoURL == revert.RepoURL && pr.Number != revert.Number && strings.TrimSpace(pr.Title) == title {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// printRevertedChanges prints the appendix listing the changes left out of
+// the notes because they were reverted
+func printRevertedChanges(changes []RevertedChange) {
+	if len(changes) == 0 {
+		return
+	}
+
+	out := reviewOutput()
+	fmt.Fprintln(out, "Reverted changes (excluded from the release notes):")
+	fmt.Fprintln(out)
+	for _, change := range changes {
+		repoName := repoNameFromURL(change.Revert.RepoURL)
+		if change.Original == nil {
+			fmt.Fprintf(out, "- %s#%d: %s (original PR not in this milestone)\n", repoName, change.Revert.Number, change.Revert.Title)
+			continue
+		}
+
+		action := "reverts"
+		if revertDepth(change.Original.Title) > 0 {
+			action = "re-lands the change reverted by"
+		}
+		fmt.Fprintf(out, "- %s#%d %s #%d: %s\n", repoName, change.Revert.Number, action, change.Original.Number, change.Original.Title)
+	}
+	fmt.Fprintln(out)
+}
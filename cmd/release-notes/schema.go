@@ -0,0 +1,160 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// fileSchema describes the versioned format of a file written by the tool.
+// Changes that older versions of the tool can't read bump the version and
+// add a migration, so existing files keep working: they are upgraded in
+// memory when read, and on disk by the migrate command.
+type fileSchema struct {
+	Name    string
+	Version int
+	// Migrations[i] upgrades a document from version i to i+1
+	Migrations []func(doc map[string]any) error
+}
+
+// migrateUnversioned upgrades the files written before the schemas were
+// versioned, which only lack the version
+func migrateUnversioned(doc map[string]any) error {
+	return nil
+}
+
+var (
+	releaseSetSchema = fileSchema{Name: "release set", Version: 2, Migrations: []func(map[string]any) error{migrateUnversioned, migrateExclusions}}
+	bundleSchema     = fileSchema{Name: "bundle", Version: 1, Migrations: []func(map[string]any) error{migrateUnversioned}}
+	snapshotSchema   = fileSchema{Name: "release candidate snapshot", Version: 1, Migrations: []func(map[string]any) error{migrateUnversioned}}
+)
+
+// migrate upgrades a decoded document to the current version of the schema
+// and reports whether it was changed. Documents written by a newer version
+// of the tool are rejected.
+func (s fileSchema) migrate(doc map[string]any) (bool, error) {
+	version := 0
+	switch v := doc["schema_version"].(type) {
+	case nil:
+	case int:
+		version = v
+	case float64:
+		version = int(v)
+	default:
+		return false, fmt.Errorf("invalid schema_version %v", v)
+	}
+
+	if version > s.Version {
+		return false, fmt.Errorf("%s schema version %d is newer than the supported %d, upgrade the tool", s.Name, version, s.Version)
+	}
+
+	migrated := version < s.Version
+	for ; version < s.Version; version++ {
+		if err := s.Migrations[version](doc); err != nil {
+			return false, fmt.Errorf("error migrating %s from schema version %d: %w", s.Name, version, err)
+		}
+		doc["schema_version"] = version + 1
+	}
+
+	return migrated, nil
+}
+
+// decodeJSON decodes a JSON document of the schema into v, migrating it to
+// the current version first, and reports whether it was migrated
+func (s fileSchema) decodeJSON(data []byte, v any) (bool, error) {
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return false, err
+	}
+
+	migrated, err := s.migrate(doc)
+	if err != nil {
+		return false, err
+	}
+
+	if data, err = json.Marshal(doc); err != nil {
+		return false, err
+	}
+	return migrated, json.Unmarshal(data, v)
+}
+
+// decodeYAML is like decodeJSON for YAML documents
+func (s fileSchema) decodeYAML(data []byte, v any) (bool, error) {
+	var doc map[string]any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return false, err
+	}
+
+	migrated, err := s.migrate(doc)
+	if err != nil {
+		return false, err
+	}
+
+	if data, err = yaml.Marshal(doc); err != nil {
+		return false, err
+	}
+	return migrated, yaml.Unmarshal(data, v)
+}
+
+// runMigrate implements the migrate command, which upgrades the given files
+// written by older versions of the tool to the current schemas in place:
+// bundles (.json.gz), release sets (.yaml) and release candidate snapshots
+// (.json). Without files, every stored snapshot is migrated.
+func runMigrate() error {
+	paths := flag.Args()
+	if len(paths) == 0 {
+		dir, err := snapshotsDir()
+		if err != nil {
+			return err
+		}
+		if paths, err = filepath.Glob(filepath.Join(dir, "*.json")); err != nil {
+			return err
+		}
+	}
+
+	for _, path := range paths {
+		migrated, err := migrateFile(path)
+		if err != nil {
+			return fmt.Errorf("error migrating %s: %w", path, err)
+		}
+
+		if migrated {
+			fmt.Printf("Migrated %s\n", path)
+		} else {
+			fmt.Printf("%s is up to date\n", path)
+		}
+	}
+
+	return nil
+}
+
+// migrateFile upgrades a file to the current schema of its kind, rewriting it
+// only when it was migrated
+func migrateFile(path string) (bool, error) {
+	switch {
+	case strings.HasSuffix(path, ".json.gz"):
+		bundle, migrated, err := loadBundle(path)
+		if err != nil || !migrated {
+			return false, err
+		}
+		return true, writeBundle(path, bundle)
+	case strings.HasSuffix(path, ".yaml"), strings.HasSuffix(path, ".yml"):
+		set, migrated, err := loadReleaseSet(path)
+		if err != nil || !migrated {
+			return false, err
+		}
+		return true, writeReleaseSet(path, set)
+	case strings.HasSuffix(path, ".json"):
+		snapshot, migrated, err := loadRCSnapshot(path)
+		if err != nil || !migrated {
+			return false, err
+		}
+		return true, writeRCSnapshot(path, snapshot)
+	default:
+		return false, fmt.Errorf("unknown file type, expected .json.gz, .yaml or .json")
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// defaultProfanityWords are the words flagged by the screening when the
+// configuration doesn't define them
+var defaultProfanityWords = []string{"crap", "damn", "fuck", "fucking", "shit", "bullshit", "wtf"}
+
+// defaultInternalURLPatterns match the URLs of internal tickets and docs,
+// flagged by the screening when the configuration doesn't define them
+var defaultInternalURLPatterns = []string{
+	`https?://[\w.-]+\.atlassian\.net(/[^\s)\]>]*[^\s)\]>.,;:])?`,
+	`https?://docs\.google\.com(/[^\s)\]>]*[^\s)\]>.,;:])?`,
+}
+
+// emailRegexp matches e-mail addresses
+var emailRegexp = regexp.MustCompile(`[\w.%+-]+@[\w-]+(\.[\w-]+)*\.[A-Za-z]{2,}`)
+
+// Finding is a match of the screening in the notes to publish
+type Finding struct {
+	Line  int
+	Kind  string
+	Match string
+}
+
+// screener flags profanity, e-mail addresses and internal URLs
+type screener struct {
+	kinds    []string
+	patterns []*regexp.Regexp
+}
+
+// newScreener builds the screener from the configuration
+func newScreener() (*screener, error) {
+	s := &screener{}
+
+	words := make([]string, 0, len(config.ProfanityWords))
+	for _, word := range config.ProfanityWords {
+		words = append(words, regexp.QuoteMeta(word))
+	}
+	if len(words) > 0 {
+		s.add("profanity", regexp.MustCompile(`(?i)\b(?:`+strings.Join(words, "|")+`)\b`))
+	}
+
+	s.add("e-mail address", emailRegexp)
+
+	for _, pattern := range config.InternalURLPatterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid internal URL pattern %q: %w", pattern, err)
+		}
+		s.add("internal URL", re)
+	}
+
+	return s, nil
+}
+
+func (s *screener) add(kind string, re *regexp.Regexp) {
+	s.kinds = append(s.kinds, kind)
+	s.patterns = append(s.patterns, re)
+}
+
+// screen returns the findings in the notes, by line
+func (s *screener) screen(notes string) []Finding {
+	var findings []Finding
+	for i, line := range strings.Split(notes, "\n") {
+		for j, re := range s.patterns {
+			for _, match := range re.FindAllString(line, -1) {
+				findings = append(findings, Finding{Line: i + 1, Kind: s.kinds[j], Match: match})
+			}
+		}
+	}
+	return findings
+}
+
+// redact replaces the findings in the notes with [REDACTED]
+func (s *screener) redact(notes string) string {
+	for _, re := range s.patterns {
+		notes = re.ReplaceAllLiteralString(notes, "[REDACTED]")
+	}
+	return notes
+}
+
+// screenNotes flags profanity, e-mail addresses and internal URLs in the
+// notes to publish. Flagged notes fail unless --allow-flagged is given, or
+// are redacted with --redact-flagged.
+func screenNotes(notes string) (string, error) {
+	s, err := newScreener()
+	if err != nil {
+		return notes, err
+	}
+
+	findings := s.screen(notes)
+	if len(findings) == 0 {
+		return notes, nil
+	}
+
+	fmt.Println("The notes contain text that shouldn't be published:")
+	fmt.Println()
+	for _, finding := range findings {
+		fmt.Printf("- line %d: %s %q\n", finding.Line, finding.Kind, finding.Match)
+	}
+	fmt.Println()
+
+	switch {
+	case allowFlagged:
+		fmt.Println("Publishing anyway because of --allow-flagged")
+		return notes, nil
+	case redactFlagged:
+		fmt.Println("Publishing with the flagged text redacted because of --redact-flagged")
+		return s.redact(notes), nil
+	default:
+		return notes, fmt.Errorf("%d flagged matches in the notes, fix them, re-run with --redact-flagged to redact them, or with --allow-flagged to publish them anyway", len(findings))
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// Section is an additional section of the notes, collecting the PRs with any
+// of its labels or changing any of its paths
+type Section struct {
+	Title  string   `yaml:"title"`
+	Labels []string `yaml:"labels"`
+	Paths  []string `yaml:"paths"`
+
+	pathRegexps []*regexp.Regexp
+}
+
+// apiChangesSection collects the changes to the public Go API used by plugin
+// and webapp developers
+var apiChangesSection = mustCompileSection(Section{
+	Title:  "Developer-facing API changes",
+	Labels: []string{"api-change"},
+	Paths:  []string{"server/public/**"},
+})
+
+// eventChangesSection collects the changes to the websocket event definitions
+var eventChangesSection = mustCompileSection(Section{
+	Title:  "Event changes",
+	Labels: []string{"websocket-change"},
+	Paths:  []string{"server/public/model/websocket_message.go"},
+})
+
+// publicModulePrefix is the directory of the server/public Go module in the
+// monorepo, documented on pkg.go.dev
+const publicModulePrefix = "server/public/"
+
+// compileSections compiles the path patterns of the sections
+func compileSections(sections []Section) error {
+	for i, section := range sections {
+		exprs, err := compilePathPatterns(section.Paths)
+		if err != nil {
+			return fmt.Errorf("section %q: %w", section.Title, err)
+		}
+		sections[i].pathRegexps = exprs
+	}
+	return nil
+}
+
+// mustCompileSection compiles a built-in section
+func mustCompileSection(section Section) Section {
+	sections := []Section{section}
+	if err := compileSections(sections); err != nil {
+		panic(err)
+	}
+	return sections[0]
+}
+
+// matchesSection reports whether the PR belongs to the section. PRs must have
+// their files fetched beforehand when the section has paths.
+func matchesSection(pr PullRequest, section Section) bool {
+	if hasAnyLabel(pr, section.Labels) {
+		return true
+	}
+
+	for _, file := range pr.Files {
+		if matchAnyPath(section.pathRegexps, file) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// godocLinks returns the pkg.go.dev links of the public Go packages changed
+// by a monorepo PR
+func godocLinks(pr PullRequest) []string {
+	if pr.RepoURL != mattermostRepoURL {
+		return nil
+	}
+
+	var links []string
+	for _, file := range pr.Files {
+		if !strings.HasPrefix(file, publicModulePrefix) || !strings.HasSuffix(file, ".go") || strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+
+		link := "https://pkg.go.dev/github.com/mattermost/mattermost/" + path.Dir(file)
+		if !containsString(links, link) {
+			links = append(links, link)
+		}
+	}
+
+	return links
+}
+
+// printSection prints the PRs belonging to a section, followed by the details
+// returned by the optional details function
+func printSection(section Section, prs []PullRequest, details func(PullRequest) []string) {
+	var matching []PullRequest
+	for _, pr := range prs {
+		if matchesSection(pr, section) {
+			matching = append(matching, pr)
+		}
+	}
+
+	if len(matching) == 0 {
+		return
+	}
+
+	fmt.Printf("%s:\n\n", section.Title)
+	for _, pr := range matching {
+		fmt.Printf("- %s (%s)\n", releaseNoteForPR(pr), prRef(pr))
+		if details == nil {
+			continue
+		}
+		for _, detail := range details(pr) {
+			fmt.Printf("  - %s\n", detail)
+		}
+	}
+	fmt.Println()
+	printStats(prsStats(matching))
+}
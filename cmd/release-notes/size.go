@@ -0,0 +1,29 @@
+package main
+
+import "sort"
+
+// Thresholds, in changed lines, used to classify the impact of a PR
+const (
+	mediumImpactLines = 100
+	largeImpactLines  = 500
+)
+
+// impactHint classifies a PR as S, M or L by the number of changed lines
+func impactHint(pr PullRequest) string {
+	changed := pr.Additions + pr.Deletions
+	switch {
+	case changed >= largeImpactLines:
+		return "L"
+	case changed >= mediumImpactLines:
+		return "M"
+	default:
+		return "S"
+	}
+}
+
+// sortPRsBySize orders the PRs by number of changed lines, largest first
+func sortPRsBySize(prs []PullRequest) {
+	sort.SliceStable(prs, func(i, j int) bool {
+		return prs[i].Additions+prs[i].Deletions > prs[j].Additions+prs[j].Deletions
+	})
+}
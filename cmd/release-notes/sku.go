@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SKU is an edition of the product some changes only apply to, e.g. the
+// Enterprise features or the Cloud-only ones. The PRs are tagged with the
+// SKUs of their labels, and the ones without any apply to every SKU.
+type SKU struct {
+	Name string `yaml:"name"`
+	// Labels tag the PRs with the SKU
+	Labels []string `yaml:"labels"`
+	// Includes are the SKUs whose changes also apply to this one, e.g. the
+	// Professional features to Enterprise
+	Includes []string `yaml:"includes"`
+}
+
+// defaultSKUs are the Mattermost editions used when the configuration
+// doesn't define any
+var defaultSKUs = []SKU{
+	{Name: "professional", Labels: []string{"sku/professional"}},
+	{Name: "enterprise", Labels: []string{"sku/enterprise"}, Includes: []string{"professional"}},
+	{Name: "cloud", Labels: []string{"sku/cloud", "cloud-only"}},
+}
+
+// skuFilter is the SKU of the --sku flag, leaving out the changes that
+// don't apply to it from customer-facing documents
+var skuFilter string
+
+// findSKU returns the configured SKU with the name, ignoring case
+func findSKU(name string) (SKU, bool) {
+	for _, sku := range config.SKUs {
+		if strings.EqualFold(sku.Name, name) {
+			return sku, true
+		}
+	}
+	return SKU{}, false
+}
+
+// skuNames returns the names of the configured SKUs
+func skuNames() []string {
+	var names []string
+	for _, sku := range config.SKUs {
+		names = append(names, sku.Name)
+	}
+	return names
+}
+
+// prSKUs returns the SKUs a PR is tagged with by its labels
+func prSKUs(pr PullRequest) []string {
+	var tags []string
+	for _, sku := range config.SKUs {
+		if hasAnyLabel(pr, sku.Labels) {
+			tags = append(tags, sku.Name)
+		}
+	}
+	return tags
+}
+
+// applicableSKUs returns the SKU with the name along with the ones it
+// includes, directly or through another included SKU
+func applicableSKUs(name string) map[string]bool {
+	applicable := make(map[string]bool)
+	pending := []string{name}
+	for len(pending) > 0 {
+		sku, ok := findSKU(pending[0])
+		pending = pending[1:]
+		if !ok || applicable[strings.ToLower(sku.Name)] {
+			continue
+		}
+		applicable[strings.ToLower(sku.Name)] = true
+		pending = append(pending, sku.Includes...)
+	}
+	return applicable
+}
+
+// filterPRsBySKU keeps the PRs applying to the SKU: the ones without SKU
+// tags and the ones tagged with the SKU or one it includes. An empty SKU
+// keeps every PR.
+func filterPRsBySKU(prs []PullRequest, name string) []PullRequest {
+	if name == "" {
+		return prs
+	}
+
+	applicable := applicableSKUs(name)
+	var result []PullRequest
+	for _, pr := range prs {
+		tags := prSKUs(pr)
+		keep := len(tags) == 0
+		for _, tag := range tags {
+			if applicable[strings.ToLower(tag)] {
+				keep = true
+				break
+			}
+		}
+		if !keep {
+			logger.Debug("Skipping PR not applying to the SKU", "pr", prKey(pr), "sku", name, "tags", strings.Join(tags, ","))
+			continue
+		}
+		result = append(result, pr)
+	}
+	return result
+}
+
+// validateSKUs checks that the SKUs have distinct names and only include
+// configured SKUs
+func validateSKUs(skus []SKU) error {
+	names := make(map[string]bool)
+	for _, sku := range skus {
+		if sku.Name == "" {
+			return fmt.Errorf("SKU without a name")
+		}
+		if names[strings.ToLower(sku.Name)] {
+			return fmt.Errorf("duplicate SKU %s", sku.Name)
+		}
+		if len(sku.Labels) == 0 {
+			return fmt.Errorf("SKU %s has no labels", sku.Name)
+		}
+		names[strings.ToLower(sku.Name)] = true
+	}
+	for _, sku := range skus {
+		for _, included := range sku.Includes {
+			if !names[strings.ToLower(included)] {
+				return fmt.Errorf("SKU %s includes unknown SKU %q", sku.Name, included)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// RCSnapshot holds the notes generated for a release candidate, stored so
+// the GA notes can be assembled from all the release candidates of a cycle
+type RCSnapshot struct {
+	SchemaVersion int             `json:"schema_version"`
+	Milestone     string          `json:"milestone"`
+	RC            int             `json:"rc"`
+	CreatedAt     time.Time       `json:"created_at"`
+	Entries       []SnapshotEntry `json:"entries"`
+}
+
+// SnapshotEntry is a PR and its release note in a snapshot
+type SnapshotEntry struct {
+	Repo   string `json:"repo"`
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	Note   string `json:"note"`
+	// RCFix is set for fixes of bugs introduced by an earlier release
+	// candidate of the same cycle, which never shipped in a release
+	RCFix bool `json:"rc_fix,omitempty"`
+	// Provenance records where the note came from, for audits
+	Provenance *Provenance `json:"provenance,omitempty"`
+}
+
+// rcFixRegexp matches the marker of fixes for bugs introduced during the
+// release cycle, e.g. "Fixes a bug introduced in RC1"
+var rcFixRegexp = regexp.MustCompile(`(?i)fix(es|ed)?\s+(a\s+|the\s+)?(bug|regression|issue)\s+introduced\s+in\s+(an?\s+|the\s+)?(rc|release candidate)`)
+
+// snapshotsDir returns the directory where the snapshots are stored
+func snapshotsDir() (string, error) {
+	if snapshotDir != "" {
+		return snapshotDir, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, ".release-notes-extractor", "snapshots"), nil
+}
+
+// snapshotFileName returns the file name of the snapshot of a release
+// candidate, replacing the characters not safe in file names. Snapshots are
+// named after the milestone title, which doesn't change when a public name
+// is mapped to it during the cycle.
+func snapshotFileName(milestoneTitle string, rc int) string {
+	safe := strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == ':' || r == ' ' {
+			return '_'
+		}
+		return r
+	}, milestoneTitle)
+
+	return fmt.Sprintf("%s-rc%d.json", safe, rc)
+}
+
+// saveRCSnapshot stores the notes of a release candidate and returns the
+// path of the snapshot file
+func saveRCSnapshot(milestoneTitle string, rc int, prs []PullRequest) (string, error) {
+	snapshot := RCSnapshot{Milestone: milestoneTitle, RC: rc, CreatedAt: time.Now().UTC()}
+	for _, pr := range prs {
+		provenance := newProvenance(pr, snapshot.CreatedAt)
+		snapshot.Entries = append(snapshot.Entries, SnapshotEntry{
+			Repo:       repoNameFromURL(pr.RepoURL),
+			Number:     pr.Number,
+			Title:      pr.Title,
+			Note:       releaseNoteForPR(pr),
+			RCFix:      rcFixRegexp.MatchString(pr.Body),
+			Provenance: &provenance,
+		})
+	}
+
+	dir, err := snapshotsDir()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, snapshotFileName(milestoneTitle, rc))
+	return path, writeRCSnapshot(path, snapshot)
+}
+
+// writeRCSnapshot writes a snapshot file with the current schema version
+func writeRCSnapshot(path string, snapshot RCSnapshot) error {
+	snapshot.SchemaVersion = snapshotSchema.Version
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0644)
+}
+
+// loadRCSnapshot reads a snapshot file, migrating it to the current schema,
+// and reports whether it was migrated
+func loadRCSnapshot(path string) (RCSnapshot, bool, error) {
+	var snapshot RCSnapshot
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return snapshot, false, err
+	}
+
+	migrated, err := snapshotSchema.decodeJSON(data, &snapshot)
+	if err != nil {
+		return snapshot, false, fmt.Errorf("invalid snapshot %s: %w", path, err)
+	}
+
+	return snapshot, migrated, nil
+}
+
+// loadRCSnapshots returns the stored snapshots of the release candidates of
+// a milestone, in release candidate order
+func loadRCSnapshots(milestoneTitle string) ([]RCSnapshot, error) {
+	dir, err := snapshotsDir()
+	if err != nil {
+		return nil, err
+	}
+
+	pattern := strings.TrimSuffix(snapshotFileName(milestoneTitle, 0), "0.json") + "*.json"
+	paths, err := filepath.Glob(filepath.Join(dir, pattern))
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshots []RCSnapshot
+	for _, path := range paths {
+		snapshot, _, err := loadRCSnapshot(path)
+		if err != nil {
+			return nil, err
+		}
+		if snapshot.Milestone == milestoneTitle {
+			snapshots = append(snapshots, snapshot)
+		}
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].RC < snapshots[j].RC
+	})
+
+	return snapshots, nil
+}
+
+// assembleGA merges the snapshots of the release candidates into the GA
+// notes. Entries are listed once, with the note of the latest release
+// candidate, and fixes for bugs introduced during the cycle are dropped.
+func assembleGA(snapshots []RCSnapshot) (entries []SnapshotEntry, dropped []SnapshotEntry) {
+	index := make(map[string]int)
+	for _, snapshot := range snapshots {
+		for _, entry := range snapshot.Entries {
+			key := fmt.Sprintf("%s#%d", entry.Repo, entry.Number)
+			if entry.Provenance != nil && entry.Provenance.Extractor == extractorManual {
+				// Hand-written entries have no PR number
+				key = "manual:" + entry.Title
+			}
+			if i, ok := index[key]; ok {
+				entries[i] = entry
+				continue
+			}
+			index[key] = len(entries)
+			entries = append(entries, entry)
+		}
+	}
+
+	var kept []SnapshotEntry
+	for _, entry := range entries {
+		if entry.RCFix {
+			dropped = append(dropped, entry)
+		} else {
+			kept = append(kept, entry)
+		}
+	}
+
+	return kept, dropped
+}
+
+// runAssembleGA implements the assemble-ga command, which prints the GA
+// notes of a milestone assembled from its stored release candidate snapshots
+func runAssembleGA() error {
+	if milestoneFlag == "" {
+		return fmt.Errorf("the --milestone flag is required")
+	}
+
+	snapshots, err := loadRCSnapshots(milestoneFlag)
+	if err != nil {
+		return err
+	}
+	if len(snapshots) == 0 {
+		return fmt.Errorf("no release candidate snapshots found for milestone %s, generate them with --rc", milestoneFlag)
+	}
+
+	entries, dropped := assembleGA(snapshots)
+
+	fmt.Printf("PRs with release notes in milestone %s (assembled from %d release candidates):\n\n", milestoneHeader(milestoneFlag), len(snapshots))
+	for _, entry := range entries {
+		fmt.Printf("%s#%d: %s\n", entry.Repo, entry.Number, entry.Title)
+		fmt.Printf("Release Note: %s\n\n", entry.Note)
+	}
+
+	if len(dropped) > 0 {
+		fmt.Println("Fixes for bugs introduced during the release cycle (excluded from the release notes):")
+		fmt.Println()
+		for _, entry := range dropped {
+			fmt.Printf("- %s#%d: %s\n", entry.Repo, entry.Number, entry.Title)
+		}
+		fmt.Println()
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// StaleEntry is an entry whose source PR description changed after its note
+// was extracted
+type StaleEntry struct {
+	Entry     ReleaseEntry
+	UpdatedAt time.Time
+}
+
+// findStaleEntries re-fetches the descriptions of the PRs of the release set
+// and returns the entries whose description changed since the extraction.
+// Entries without provenance can't be checked and are skipped, like the
+// hand-written ones, the ones imported from a changelog and the excluded
+// ones, which are not published.
+func findStaleEntries(set ReleaseSet) ([]StaleEntry, error) {
+	var stale []StaleEntry
+	for _, entry := range set.Entries {
+		if entry.Provenance == nil || entry.Provenance.Extractor == extractorManual || entry.Provenance.Extractor == extractorChangelog || entry.Excluded != nil {
+			continue
+		}
+
+		var pr struct {
+			Body      string    `json:"body"`
+			UpdatedAt time.Time `json:"updated_at"`
+		}
+		url := fmt.Sprintf("%s/pulls/%d", repoURLFromName(entry.Repo), entry.Number)
+		if err := apiClient.GetJSON(url, &pr); err != nil {
+			return nil, fmt.Errorf("error getting %s#%d: %w", entry.Repo, entry.Number, err)
+		}
+
+		if bodySHA256(pr.Body) != entry.Provenance.BodySHA256 {
+			stale = append(stale, StaleEntry{Entry: entry, UpdatedAt: pr.UpdatedAt})
+		}
+	}
+
+	return stale, nil
+}
+
+// checkReleaseSetFresh fails when the description of a PR of the release set
+// changed after the extraction, unless forced, so notes edited at the last
+// minute are not published stale
+func checkReleaseSetFresh(path string) error {
+	set, _, err := loadReleaseSet(path)
+	if err != nil {
+		return err
+	}
+
+	stale, err := findStaleEntries(set)
+	if err != nil {
+		return err
+	}
+	if len(stale) == 0 {
+		return nil
+	}
+
+	fmt.Printf("The descriptions of these PRs changed after their notes were extracted on %s:\n\n", set.GeneratedAt.Format(time.RFC3339))
+	for _, s := range stale {
+		fmt.Printf("- %s#%d: %s (updated %s)\n", s.Entry.Repo, s.Entry.Number, s.Entry.Title, s.UpdatedAt.Format(time.RFC3339))
+	}
+	fmt.Println()
+
+	if forcePublish {
+		fmt.Println("Publishing anyway because of --force")
+		return nil
+	}
+
+	return fmt.Errorf("%d notes may be stale, extract the release set again and render the notes, or re-run with --force to publish them anyway", len(stale))
+}
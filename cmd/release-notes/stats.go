@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/jespino/github-mm-release-notes/extract"
+	"github.com/jespino/github-mm-release-notes/internal/format"
+)
+
+// prsStats returns the stats of a section listing the notes of the PRs
+func prsStats(prs []PullRequest) format.SectionStats {
+	var notes []string
+	for _, pr := range prs {
+		if note := releaseNoteForPR(pr); extract.HasReleaseNote(note) {
+			notes = append(notes, note)
+		}
+	}
+	return format.NotesStats(notes)
+}
+
+// printStats prints the stats line of a section when requested
+func printStats(stats format.SectionStats) {
+	if showStats {
+		fmt.Fprintf(reviewOutput(), "Stats: %s\n\n", stats)
+	}
+}
+
+// printSectionStats prints the stats line of each type of change, the
+// sections of the Markdown notes, followed by the total, for the notes
+// listed without sections
+func printSectionStats(prs []PullRequest) {
+	if !showStats {
+		return
+	}
+	for _, group := range groupPRsByType(prs) {
+		if stats := prsStats(group.PRs); stats.Entries > 0 {
+			fmt.Fprintf(reviewOutput(), "Stats (%s): %s\n", group.Type, stats)
+		}
+	}
+	fmt.Fprintf(reviewOutput(), "Stats: %s\n\n", prsStats(prs))
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/jespino/github-mm-release-notes/extract"
+)
+
+// Term is an entry of the terminology dictionary: the preferred spelling of
+// a term and the alternatives to avoid. Spellings of the preferred term with
+// a different capitalization are also flagged.
+type Term struct {
+	Preferred string   `yaml:"preferred"`
+	Avoid     []string `yaml:"avoid"`
+
+	re *regexp.Regexp
+}
+
+// defaultTerminology is the terminology dictionary used when the
+// configuration doesn't define one
+var defaultTerminology = mustCompileTerminology([]Term{
+	{Preferred: "System Console", Avoid: []string{"admin console", "administration console"}},
+	{Preferred: "Mattermost server"},
+})
+
+// TermIssue is a use of a term not following the terminology dictionary
+type TermIssue struct {
+	Found     string
+	Preferred string
+}
+
+// compileTerminology builds the regular expressions matching the terms of
+// the dictionary, as whole words and ignoring case
+func compileTerminology(terms []Term) error {
+	for i, term := range terms {
+		if strings.TrimSpace(term.Preferred) == "" {
+			return fmt.Errorf("terminology[%d] has no preferred term", i)
+		}
+
+		alternatives := []string{regexp.QuoteMeta(term.Preferred)}
+		for _, avoid := range term.Avoid {
+			alternatives = append(alternatives, regexp.QuoteMeta(avoid))
+		}
+		terms[i].re = regexp.MustCompile(`(?i)\b(?:` + strings.Join(alternatives, "|") + `)\b`)
+	}
+	return nil
+}
+
+// mustCompileTerminology compiles a built-in terminology dictionary
+func mustCompileTerminology(terms []Term) []Term {
+	if err := compileTerminology(terms); err != nil {
+		panic(err)
+	}
+	return terms
+}
+
+// checkTerminology returns the uses of terms in a note not following the
+// terminology dictionary
+func checkTerminology(note string) []TermIssue {
+	var issues []TermIssue
+	for _, term := range config.Terminology {
+		if term.re == nil {
+			continue
+		}
+		for _, found := range term.re.FindAllString(note, -1) {
+			if found != term.Preferred {
+				issues = append(issues, TermIssue{Found: found, Preferred: term.Preferred})
+			}
+		}
+	}
+	return issues
+}
+
+// fixTerminology replaces the uses of terms in a note not following the
+// terminology dictionary with the preferred terms
+func fixTerminology(note string) string {
+	for _, term := range config.Terminology {
+		if term.re == nil {
+			continue
+		}
+		note = term.re.ReplaceAllLiteralString(note, term.Preferred)
+	}
+	return note
+}
+
+// runLint implements the lint command, which checks the notes of a release
+// set (--release-set), or of a milestone (--milestone and --repo), against
+// the terminology dictionary and fails if any note doesn't follow it
+func runLint() error {
+	var prs []PullRequest
+	switch {
+	case releaseSetFile != "":
+		set, _, err := loadReleaseSet(releaseSetFile)
+		if err != nil {
+			return err
+		}
+		prs = set.pullRequests()
+	case milestoneFlag != "":
+		repoURLs := allRepoURLs
+		if repoFlag != "" {
+			option, ok := findRepoOption(repoOptions(), repoFlag)
+			if !ok {
+				return fmt.Errorf("invalid repository %q", repoFlag)
+			}
+			repoURLs = repositoryURLs(option.Repos)
+		}
+
+		var err error
+		if _, prs, err = getMilestonePRs(milestoneFlag, repoURLs); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("either the --release-set or the --milestone flag is required")
+	}
+
+	count := 0
+	for _, pr := range prs {
+		note := releaseNoteForPR(pr)
+		if !extract.HasReleaseNote(note) {
+			continue
+		}
+		for _, issue := range checkTerminology(note) {
+			fmt.Printf("%s: uses %q, prefer %q\n", prKey(pr), issue.Found, issue.Preferred)
+			count++
+		}
+	}
+
+	if count > 0 {
+		return fmt.Errorf("%d terminology issues found, fix the notes or render with --fix-terms", count)
+	}
+
+	fmt.Printf("The notes of %d PRs follow the terminology dictionary\n", len(prs))
+	return nil
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Types of change the notes are grouped by, in the order of the official
+// Mattermost changelog
+const (
+	typeBreaking    = "Breaking Changes"
+	typeDeprecation = "Deprecations"
+	typeFeature     = "New Features"
+	typeImprovement = "Improvements"
+	typeBugFix      = "Bug Fixes"
+)
+
+var changeTypes = []string{typeBreaking, typeDeprecation, typeFeature, typeImprovement, typeBugFix}
+
+// Note prefixes marking the type of change of the PRs without a type label,
+// e.g. "Breaking change: removed the legacy API" or "Fixed an issue where..."
+var (
+	breakingPrefixRegexp    = regexp.MustCompile(`(?i)^\s*breaking(\s+changes?)?\s*[:-]`)
+	deprecationPrefixRegexp = regexp.MustCompile(`(?i)^\s*deprecat(ed|es|ion)\b`)
+	featurePrefixRegexp     = regexp.MustCompile(`(?i)^\s*(new\s+)?feature\s*[:-]`)
+	bugFixPrefixRegexp      = regexp.MustCompile(`(?i)^\s*fix(ed|es)?\b`)
+)
+
+// changeType returns the type of change of a PR, from its labels or, when it
+// has none of the type labels, from the prefix of its release note. PRs with
+// neither are uncategorized, and their type is guessed by the category rules
+// of the configuration, defaulting to an improvement, and reported as
+// provisional.
+func changeType(pr PullRequest) (string, bool) {
+	switch {
+	case hasAnyLabel(pr, config.BreakingLabels):
+		return typeBreaking, false
+	case hasAnyLabel(pr, config.DeprecationLabels):
+		return typeDeprecation, false
+	case hasAnyLabel(pr, splitList(featureLabels)):
+		return typeFeature, false
+	case hasAnyLabel(pr, config.BugLabels):
+		return typeBugFix, false
+	}
+
+	note := prReleaseNote(pr)
+	switch {
+	case breakingPrefixRegexp.MatchString(note):
+		return typeBreaking, false
+	case deprecationPrefixRegexp.MatchString(note):
+		return typeDeprecation, false
+	case featurePrefixRegexp.MatchString(note):
+		return typeFeature, false
+	case bugFixPrefixRegexp.MatchString(note):
+		return typeBugFix, false
+	}
+
+	for _, rule := range config.CategoryRules {
+		if rule.matches(pr) {
+			return rule.Category, true
+		}
+	}
+	return typeImprovement, true
+}
+
+// CategoryRule guesses the type of change of the uncategorized PRs matching
+// any of its title keywords, labels or changed paths
+type CategoryRule struct {
+	// Category is one of the types of change, e.g. "Bug Fixes"
+	Category string `yaml:"category"`
+	// TitleKeywords are matched as whole words of the PR title, ignoring case
+	TitleKeywords []string `yaml:"title_keywords"`
+	Labels        []string `yaml:"labels"`
+	Paths         []string `yaml:"paths"`
+
+	titleRegexp *regexp.Regexp
+	pathRegexps []*regexp.Regexp
+}
+
+// defaultCategoryRules are the category rules used when the configuration
+// doesn't define them
+var defaultCategoryRules = mustCompileCategoryRules([]CategoryRule{
+	{Category: typeDeprecation, TitleKeywords: []string{"deprecate", "deprecated", "deprecation"}},
+	{Category: typeBugFix, TitleKeywords: []string{"fix", "fixed", "fixes", "bug", "crash", "regression"}},
+	{Category: typeFeature, TitleKeywords: []string{"add", "adds", "added", "introduce", "introduces", "new"}},
+})
+
+// matches reports whether a PR matches the rule. PRs must have their files
+// fetched beforehand when the rule has paths.
+func (r CategoryRule) matches(pr PullRequest) bool {
+	if r.titleRegexp != nil && r.titleRegexp.MatchString(pr.Title) {
+		return true
+	}
+	if hasAnyLabel(pr, r.Labels) {
+		return true
+	}
+	for _, file := range pr.Files {
+		if matchAnyPath(r.pathRegexps, file) {
+			return true
+		}
+	}
+	return false
+}
+
+// compileCategoryRules checks the categories of the rules and builds the
+// regular expressions matching their title keywords and paths
+func compileCategoryRules(rules []CategoryRule) error {
+	for i, rule := range rules {
+		if !containsString(changeTypes, rule.Category) {
+			return fmt.Errorf("invalid category %q, must be one of: %s", rule.Category, strings.Join(changeTypes, ", "))
+		}
+
+		var keywords []string
+		for _, keyword := range rule.TitleKeywords {
+			keywords = append(keywords, regexp.QuoteMeta(keyword))
+		}
+		if len(keywords) > 0 {
+			rules[i].titleRegexp = regexp.MustCompile(`(?i)\b(?:` + strings.Join(keywords, "|") + `)\b`)
+		}
+
+		exprs, err := compilePathPatterns(rule.Paths)
+		if err != nil {
+			return err
+		}
+		rules[i].pathRegexps = exprs
+	}
+	return nil
+}
+
+// mustCompileCategoryRules compiles built-in category rules
+func mustCompileCategoryRules(rules []CategoryRule) []CategoryRule {
+	if err := compileCategoryRules(rules); err != nil {
+		panic(err)
+	}
+	return rules
+}
+
+// categoryRulesUsePaths reports whether any category rule matches changed
+// paths, which requires fetching the files of the PRs
+func categoryRulesUsePaths() bool {
+	for _, rule := range config.CategoryRules {
+		if len(rule.Paths) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// markProvisional marks the notes whose type of change was guessed, with
+// --mark-provisional, for the reviewers. The published notes are left clean.
+var markProvisional bool
+
+// provisionalMarker returns the marker of the notes whose type of change was
+// guessed, for the reviewers to confirm, only with --mark-provisional
+func provisionalMarker(pr PullRequest, markdown bool) string {
+	if !markProvisional {
+		return ""
+	}
+	if _, provisional := changeType(pr); !provisional {
+		return ""
+	}
+	if markdown {
+		return " _(provisional)_"
+	}
+	return " (provisional)"
+}
+
+// countGuessedTypes returns how many of the PRs had their type of change
+// guessed
+func countGuessedTypes(prs []PullRequest) int {
+	guessed := 0
+	for _, pr := range prs {
+		if _, provisional := changeType(pr); provisional {
+			guessed++
+		}
+	}
+	return guessed
+}
+
+// printGuessRate prints how many of the PRs had their type of change guessed
+func printGuessRate(prs []PullRequest) {
+	guessed := countGuessedTypes(prs)
+	if guessed == 0 {
+		return
+	}
+
+	fmt.Fprintf(reviewOutput(), "Type of change guessed for %d of %d entries (%d%%), review the provisional ones.\n\n", guessed, len(prs), guessed*100/len(prs))
+}
+
+// TypeGroup is a type of change and the PRs of that type
+type TypeGroup struct {
+	Type string
+	PRs  []PullRequest
+}
+
+// groupPRsByType groups the PRs by their type of change, leaving out the
+// types without PRs
+func groupPRsByType(prs []PullRequest) []TypeGroup {
+	byType := make(map[string][]PullRequest)
+	for _, pr := range prs {
+		t, _ := changeType(pr)
+		byType[t] = append(byType[t], pr)
+	}
+
+	var groups []TypeGroup
+	for _, t := range changeTypes {
+		if len(byType[t]) > 0 {
+			groups = append(groups, TypeGroup{Type: t, PRs: byType[t]})
+		}
+	}
+	return groups
+}
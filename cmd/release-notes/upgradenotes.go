@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jespino/github-mm-release-notes/extract"
+	"github.com/jespino/github-mm-release-notes/internal/format"
+)
+
+// trimZeroVersion removes the trailing zero components of a version, so
+// v9.11 and v9.11.0 compare equal
+func trimZeroVersion(version string) string {
+	for strings.HasSuffix(version, ".0") {
+		version = strings.TrimSuffix(version, ".0")
+	}
+	return version
+}
+
+// loadLedger returns the release sets in the backfill directory, written by
+// the backfill and import-changelog commands, of the versions after from up
+// to to, including the patch releases of both, sorted by version
+func loadLedger(from string, to string) ([]ReleaseSet, error) {
+	paths, err := filepath.Glob(filepath.Join(backfillDir, "*.yaml"))
+	if err != nil {
+		return nil, err
+	}
+
+	var sets []ReleaseSet
+	for _, path := range paths {
+		set, _, err := loadReleaseSet(path)
+		if err != nil {
+			return nil, err
+		}
+		if compareVersionTitles(trimZeroVersion(set.Milestone), trimZeroVersion(from)) <= 0 || !inVersionRange(set.Milestone, "", to) {
+			continue
+		}
+		sets = append(sets, set)
+	}
+
+	sort.Slice(sets, func(i, j int) bool {
+		return compareVersionTitles(sets[i].Milestone, sets[j].Milestone) < 0
+	})
+	return sets, nil
+}
+
+// upgradeEntryKey identifies an entry across the release sets, the same PR
+// being listed in several when it was carried over
+func upgradeEntryKey(pr PullRequest) string {
+	if pr.Manual {
+		return normalizeNote(prReleaseNote(pr))
+	}
+	return prKey(pr)
+}
+
+// runUpgradeNotes implements the upgrade-notes command, which generates the
+// document of the changes between two versions for the customers upgrading:
+// the entries of every version after --from up to --to in the release sets
+// of --backfill-dir, de-duplicated and grouped by type of change, breaking
+// changes first, each with the version it shipped in
+func runUpgradeNotes() error {
+	if backfillFrom == "" || backfillTo == "" {
+		return fmt.Errorf("the --from flag with the installed version and the --to flag with the target version are required")
+	}
+
+	if outputFormat == formatHTML {
+		return fmt.Errorf("the upgrade-notes command supports the text and markdown formats")
+	}
+
+	sets, err := loadLedger(backfillFrom, backfillTo)
+	if err != nil {
+		return err
+	}
+	if len(sets) == 0 {
+		return fmt.Errorf("no release sets of the versions after %s up to %s found in %s, extract them with the backfill or import-changelog commands", backfillFrom, backfillTo, backfillDir)
+	}
+
+	// The entries are concatenated in version order, an entry listed in
+	// several versions keeping the first one
+	var prs []PullRequest
+	versions := make(map[string]string)
+	var titles []string
+	for _, set := range sets {
+		titles = append(titles, milestoneDisplayName(set.Milestone))
+		kept, _ := separateNoneNotes(filterPRsBySKU(set.pullRequests(), skuFilter))
+		for _, pr := range kept {
+			if !extract.HasReleaseNote(prReleaseNote(pr)) {
+				continue
+			}
+			key := upgradeEntryKey(pr)
+			if _, ok := versions[key]; ok {
+				continue
+			}
+			versions[key] = milestoneDisplayName(set.Milestone)
+			prs = append(prs, pr)
+		}
+	}
+
+	// The same note shipped in several versions, e.g. backported to a patch
+	// release, is listed once
+	if dedupMode == "" {
+		dedupMode = dedupExact
+	}
+	if prs, err = applyDedup(prs); err != nil {
+		return err
+	}
+
+	if outputFile != "" {
+		restore, err := redirectOutput(outputFile)
+		if err != nil {
+			return err
+		}
+		defer restore()
+	}
+
+	repos := make(map[string]bool)
+	for _, pr := range prs {
+		repos[pr.RepoURL] = true
+		for _, duplicate := range pr.Duplicates {
+			repos[duplicate.RepoURL] = true
+		}
+	}
+
+	if outputFormat == formatMarkdown {
+		fmt.Printf("# Upgrading from %s to %s\n\n", backfillFrom, backfillTo)
+		fmt.Printf("Changes of %s.\n\n", strings.Join(titles, ", "))
+	} else {
+		fmt.Printf("Changes upgrading from %s to %s, in %s:\n\n", backfillFrom, backfillTo, strings.Join(titles, ", "))
+	}
+
+	for _, group := range groupPRsByType(prs) {
+		if outputFormat == formatMarkdown {
+			fmt.Printf("## %s\n\n", group.Type)
+		} else {
+			fmt.Printf("%s:\n\n", group.Type)
+		}
+
+		for _, pr := range group.PRs {
+			version := versions[upgradeEntryKey(pr)]
+			note := releaseNoteForPR(pr)
+			switch {
+			case outputFormat != formatMarkdown:
+				fmt.Printf("- [%s] %s: %s\n", version, prLabel(pr), strings.ReplaceAll(note, "\n", "\n  "))
+			case pr.Manual:
+				fmt.Printf("- %s (%s)\n", format.MarkdownBullet(note), version)
+			default:
+				fmt.Printf("- %s (%s, %s)\n", format.MarkdownBullet(note), markdownPRLinks(pr, len(repos) > 1), version)
+			}
+		}
+		fmt.Println()
+	}
+
+	return nil
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+)
+
+// prKey identifies a PR across repositories
+func prKey(pr PullRequest) string {
+	return fmt.Sprintf("%s#%d", repoNameFromURL(pr.RepoURL), pr.Number)
+}
+
+// runWatch implements the watch command, which polls the milestone for PRs
+// gaining or losing the release-note label, e.g. during the code freeze, and
+// reports every change until interrupted
+func runWatch() error {
+	if milestoneFlag == "" {
+		return fmt.Errorf("the --milestone flag is required")
+	}
+	repoURLs := allRepoURLs
+	if repoFlag != "" {
+		option, ok := findRepoOption(repoOptions(), repoFlag)
+		if !ok {
+			return fmt.Errorf("invalid repository %q", repoFlag)
+		}
+		repoURLs = repositoryURLs(option.Repos)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	var previous map[string]PullRequest
+	for {
+		milestones, prs, err := getMilestonePRs(milestoneFlag, repoURLs)
+		if err == nil && len(milestones) == 0 {
+			return fmt.Errorf("no open milestone %s found", milestoneFlag)
+		}
+		if err != nil {
+			// Keep watching through transient errors
+			logger.Error("Error getting PRs", "error", err)
+		} else {
+			current := make(map[string]PullRequest, len(prs))
+			for _, pr := range prs {
+				current[prKey(pr)] = pr
+			}
+
+			if previous == nil {
+				fmt.Printf("%s Watching %d PRs with release notes in milestone %s, polling every %s\n", time.Now().Format(time.TimeOnly), len(current), milestoneFlag, watchInterval)
+			} else {
+				for key, pr := range current {
+					if _, ok := previous[key]; !ok {
+						notifyLabelChange(fmt.Sprintf("%s gained the %s label: %s", key, strings.Join(releaseNoteLabels(pr.RepoURL), "/"), pr.Title))
+					}
+				}
+				for key, pr := range previous {
+					if _, ok := current[key]; !ok {
+						notifyLabelChange(fmt.Sprintf("%s lost the %s label or left the milestone: %s", key, strings.Join(releaseNoteLabels(pr.RepoURL), "/"), pr.Title))
+					}
+				}
+			}
+			previous = current
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(watchInterval):
+		}
+	}
+}
+
+// notifyLabelChange prints a change and posts it to the --notify-url
+// incoming webhook, if any
+func notifyLabelChange(message string) {
+	fmt.Printf("%s %s\n", time.Now().Format(time.TimeOnly), message)
+
+	if notifyURL == "" {
+		return
+	}
+
+	payload, _ := json.Marshal(map[string]string{"text": message})
+	resp, err := http.Post(notifyURL, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		logger.Error("Error posting notification", "error", err)
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		logger.Error("Error posting notification", "status", resp.StatusCode)
+	}
+}
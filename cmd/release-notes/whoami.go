@@ -0,0 +1,118 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jespino/github-mm-release-notes/github"
+)
+
+// runWhoami implements the whoami command, which prints the identity behind
+// the token, its scopes, its access to the repositories and the remaining
+// API quota, the first things to check when access fails
+func runWhoami() error {
+	if authToken == "" {
+		fmt.Println("Not authenticated: no GitHub token provided.")
+	} else {
+		var user struct {
+			Login string `json:"login"`
+			Name  string `json:"name"`
+		}
+		header, err := apiClient.GetJSONWithHeader(githubAPIURL+"/user", &user)
+		if err != nil {
+			return fmt.Errorf("error getting the authenticated user: %w", err)
+		}
+
+		fmt.Printf("Login: %s", user.Login)
+		if user.Name != "" {
+			fmt.Printf(" (%s)", user.Name)
+		}
+		fmt.Println()
+
+		// Only classic tokens report their scopes
+		if scopes, ok := header["X-Oauth-Scopes"]; ok {
+			fmt.Printf("Token scopes: %s\n", strings.Join(scopes, ", "))
+		} else {
+			fmt.Println("Token scopes: not reported (fine-grained token or GitHub App)")
+		}
+
+		fmt.Println("Organization memberships:")
+		for _, org := range repoOrgs() {
+			fmt.Printf("  %s: %s\n", org, orgMembership(org))
+		}
+	}
+
+	fmt.Println("Repository access:")
+	for _, repoURL := range allRepoURLs {
+		status := "ok"
+		if err := apiClient.GetJSON(repoURL, &struct{}{}); err != nil {
+			status = "no access"
+			var apiErr *github.APIError
+			if !errors.As(err, &apiErr) {
+				status = err.Error()
+			}
+		}
+		fmt.Printf("  %s: %s\n", repoNameFromURL(repoURL), status)
+	}
+
+	var rateLimit struct {
+		Resources map[string]struct {
+			Limit     int   `json:"limit"`
+			Remaining int   `json:"remaining"`
+			Reset     int64 `json:"reset"`
+		} `json:"resources"`
+	}
+	if err := apiClient.GetJSON(githubAPIURL+"/rate_limit", &rateLimit); err != nil {
+		return fmt.Errorf("error getting the rate limit: %w", err)
+	}
+
+	fmt.Println("Rate limit:")
+	for _, resource := range []string{"core", "graphql", "search"} {
+		limit, ok := rateLimit.Resources[resource]
+		if !ok {
+			continue
+		}
+		fmt.Printf("  %s: %d/%d remaining, resets at %s\n", resource, limit.Remaining, limit.Limit,
+			time.Unix(limit.Reset, 0).Format("15:04:05"))
+	}
+
+	return nil
+}
+
+// repoOrgs returns the owners of the repositories the tool works with
+func repoOrgs() []string {
+	var orgs []string
+	for _, repoURL := range allRepoURLs {
+		org, _, _ := strings.Cut(repoNameFromURL(repoURL), "/")
+		if !containsString(orgs, org) {
+			orgs = append(orgs, org)
+		}
+	}
+	return orgs
+}
+
+// orgMembership describes the membership of the authenticated user in an
+// organization
+func orgMembership(org string) string {
+	var membership struct {
+		State string `json:"state"`
+		Role  string `json:"role"`
+	}
+
+	err := apiClient.GetJSON(fmt.Sprintf("%s/user/memberships/orgs/%s", githubAPIURL, org), &membership)
+
+	var apiErr *github.APIError
+	switch {
+	case errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound:
+		return "not a member"
+	case errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusForbidden:
+		return "unknown (the token needs the read:org scope, or SSO authorization)"
+	case err != nil:
+		return "unknown (" + err.Error() + ")"
+	}
+
+	return fmt.Sprintf("%s (%s)", membership.Role, membership.State)
+}
@@ -0,0 +1,190 @@
+// Package extract parses the release notes and test steps out of PR
+// descriptions, in the formats supported by github-mm-release-notes, so
+// other release tooling can reuse the same parsing.
+package extract
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Placeholders returned by ReleaseNote when no note can be found
+const (
+	NoReleaseNote         = "No release note found"
+	NoReleaseNoteInFormat = "No release note found in expected format"
+)
+
+// Names of the release note extractors, returned along with the notes to
+// record where they came from
+const (
+	ExtractorBlock       = "release-note-block"
+	ExtractorSpacedBlock = "release-note-block-spaced"
+	ExtractorHeading     = "release-note-heading"
+	ExtractorPrefix      = "release-note-prefix"
+	ExtractorParagraph   = "release-note-paragraph"
+	// ExtractorCommitTrailer is recorded for the notes taken from the
+	// Release-Note: trailer of a merge commit, see CommitTrailer
+	ExtractorCommitTrailer = "commit-trailer"
+)
+
+var (
+	// Format 1: ```release-note ... ```
+	blockRegexp = regexp.MustCompile("(?s)```release-note\n(.*?)\n```")
+	// Format 2: ```release-note ... ``` (with spaces)
+	spacedBlockRegexp = regexp.MustCompile("(?s)```\\s*release-note\\s*\n(.*?)\n\\s*```")
+	// Format 3: ### Release Note ... ###
+	headingRegexp = regexp.MustCompile("(?s)###\\s*Release Note\\s*\n(.*?)(\n###|\n$)")
+	// Format 4: release-note: ...
+	prefixRegexp = regexp.MustCompile("(?s)release-note:\\s*(.*?)(\n\n|\n$)")
+	// Any paragraph with "release note" mentioned
+	paragraphRegexp = regexp.MustCompile("(?i)(?s)(?:release notes?|release changes?)[:\\s]+(.*?)(\n\n|\n$)")
+)
+
+// extractors are tried in order, the first matching one wins. The fenced
+// blocks can be repeated, e.g. with separate notes for server and webapp.
+var extractors = []struct {
+	name     string
+	re       *regexp.Regexp
+	multiple bool
+}{
+	{ExtractorBlock, blockRegexp, true},
+	{ExtractorSpacedBlock, spacedBlockRegexp, true},
+	{ExtractorHeading, headingRegexp, false},
+	{ExtractorPrefix, prefixRegexp, false},
+	{ExtractorParagraph, paragraphRegexp, false},
+}
+
+// ReleaseNotes extracts every release note of a PR description, e.g. from
+// several fenced blocks, and returns the name of the extractor that matched,
+// empty when none did, in which case there are no notes. NONE blocks are
+// left out when the description has other notes.
+func ReleaseNotes(body string) ([]string, string) {
+	if body == "" {
+		return nil, ""
+	}
+
+	for _, extractor := range extractors {
+		var all [][]string
+		if extractor.multiple {
+			all = extractor.re.FindAllStringSubmatch(body, -1)
+		} else if matches := extractor.re.FindStringSubmatch(body); matches != nil {
+			all = [][]string{matches}
+		}
+		if len(all) == 0 {
+			continue
+		}
+
+		var notes, none []string
+		for _, matches := range all {
+			note := strings.TrimSpace(matches[1])
+			if IsNone(note) {
+				none = append(none, note)
+			} else if note != "" {
+				notes = append(notes, note)
+			}
+		}
+		if len(notes) == 0 {
+			notes = none
+		}
+		if len(notes) == 0 {
+			// Only empty blocks, which read as an empty note
+			notes = []string{""}
+		}
+		return notes, extractor.name
+	}
+
+	return nil, ""
+}
+
+// ReleaseNote extracts the release note section from a PR description and
+// returns the name of the extractor that matched, empty when none did, in
+// which case the note is one of the placeholders. The notes of several
+// blocks are joined in a note with a line each.
+func ReleaseNote(body string) (string, string) {
+	if body == "" {
+		return NoReleaseNote, ""
+	}
+
+	notes, extractor := ReleaseNotes(body)
+	if extractor == "" {
+		return NoReleaseNoteInFormat, ""
+	}
+	if len(notes) > 1 && IsNone(notes[0]) {
+		return notes[0], extractor
+	}
+	return strings.Join(notes, "\n"), extractor
+}
+
+// IsNone reports whether a release note is the "NONE" placeholder of PRs
+// without user-facing changes, e.g. a ```release-note NONE``` block
+func IsNone(releaseNote string) bool {
+	return strings.EqualFold(strings.TrimSuffix(strings.TrimSpace(releaseNote), "."), "none")
+}
+
+// HasReleaseNote reports whether an extracted release note is an actual note
+// rather than a placeholder or NONE
+func HasReleaseNote(releaseNote string) bool {
+	return releaseNote != NoReleaseNote && releaseNote != NoReleaseNoteInFormat && !IsNone(releaseNote)
+}
+
+// trailerRegexp matches a "Key: value" git trailer line
+var trailerRegexp = regexp.MustCompile(`^([A-Za-z0-9-]+):\s*(.*)$`)
+
+// CommitTrailer returns the value of the Release-Note: trailer of a commit
+// message, or an empty string if there is none. Trailers are read from the
+// last paragraph of the message, and values continued on lines starting with
+// whitespace are joined, as git does.
+func CommitTrailer(message string) string {
+	message = strings.TrimSpace(strings.ReplaceAll(message, "\r\n", "\n"))
+	paragraphs := strings.Split(message, "\n\n")
+	if len(paragraphs) < 2 {
+		// A message with a single paragraph only has a subject
+		return ""
+	}
+
+	var note []string
+	inNote := false
+	for _, line := range strings.Split(paragraphs[len(paragraphs)-1], "\n") {
+		if inNote && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			note = append(note, strings.TrimSpace(line))
+			continue
+		}
+
+		inNote = false
+		matches := trailerRegexp.FindStringSubmatch(line)
+		if matches != nil && strings.EqualFold(matches[1], "Release-Note") {
+			note = append(note, strings.TrimSpace(matches[2]))
+			inNote = true
+		}
+	}
+
+	return strings.TrimSpace(strings.Join(note, " "))
+}
+
+// sectionHeadingRegexp matches any Markdown heading, ending a section
+var sectionHeadingRegexp = regexp.MustCompile(`(?m)^#{1,6}\s`)
+
+// TestPlan returns the content of the first Markdown section of the PR
+// description whose heading matches one of the given headings (e.g.
+// "Testing" or "QA Test Steps"), or an empty string if there is none
+func TestPlan(body string, headings []string) string {
+	for _, heading := range headings {
+		re := regexp.MustCompile(`(?im)^#{1,6}\s*` + regexp.QuoteMeta(heading) + `\s*:?\s*$`)
+		loc := re.FindStringIndex(body)
+		if loc == nil {
+			continue
+		}
+
+		// The section ends at the next heading
+		section := body[loc[1]:]
+		if next := sectionHeadingRegexp.FindStringIndex(section); next != nil {
+			section = section[:next[0]]
+		}
+
+		if section = strings.TrimSpace(section); section != "" {
+			return section
+		}
+	}
+
+	return ""
+}
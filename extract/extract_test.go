@@ -0,0 +1,140 @@
+package extract
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReleaseNotes(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		notes     []string
+		extractor string
+	}{
+		{
+			name:      "block",
+			body:      "Summary\n\n```release-note\nAdded the foo setting.\n```\n",
+			notes:     []string{"Added the foo setting."},
+			extractor: ExtractorBlock,
+		},
+		{
+			name:      "spaced block",
+			body:      "Summary\n\n``` release-note \nAdded the foo setting.\n ```\n",
+			notes:     []string{"Added the foo setting."},
+			extractor: ExtractorSpacedBlock,
+		},
+		{
+			name:      "heading",
+			body:      "Summary\n\n### Release Note\nAdded the foo setting.\n### Testing\nSteps\n",
+			notes:     []string{"Added the foo setting."},
+			extractor: ExtractorHeading,
+		},
+		{
+			name:      "prefix",
+			body:      "Summary\n\nrelease-note: Added the foo setting.\n\nMore details\n",
+			notes:     []string{"Added the foo setting."},
+			extractor: ExtractorPrefix,
+		},
+		{
+			name:      "paragraph",
+			body:      "Summary\n\nRelease notes: Added the foo setting.\n\nMore details\n",
+			notes:     []string{"Added the foo setting."},
+			extractor: ExtractorParagraph,
+		},
+		{
+			name:      "none",
+			body:      "```release-note\nNONE\n```\n",
+			notes:     []string{"NONE"},
+			extractor: ExtractorBlock,
+		},
+		{
+			name:      "several blocks",
+			body:      "Server:\n```release-note\nAdded the foo setting.\n```\nWebapp:\n```release-note\nAdded the foo toggle.\n```\n",
+			notes:     []string{"Added the foo setting.", "Added the foo toggle."},
+			extractor: ExtractorBlock,
+		},
+		{
+			name:      "none block along with a note",
+			body:      "```release-note\nNONE\n```\n```release-note\nAdded the foo toggle.\n```\n",
+			notes:     []string{"Added the foo toggle."},
+			extractor: ExtractorBlock,
+		},
+		{
+			name:      "no note",
+			body:      "Summary\n\nJust a refactor.\n",
+			notes:     nil,
+			extractor: "",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			notes, extractor := ReleaseNotes(test.body)
+			if !reflect.DeepEqual(notes, test.notes) {
+				t.Errorf("notes = %q, want %q", notes, test.notes)
+			}
+			if extractor != test.extractor {
+				t.Errorf("extractor = %q, want %q", extractor, test.extractor)
+			}
+		})
+	}
+}
+
+func TestReleaseNote(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		note string
+	}{
+		{name: "empty description", body: "", note: NoReleaseNote},
+		{name: "no note", body: "Just a refactor.\n", note: NoReleaseNoteInFormat},
+		{name: "block", body: "```release-note\nAdded the foo setting.\n```\n", note: "Added the foo setting."},
+		{name: "none", body: "```release-note\nNone.\n```\n", note: "None."},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if note, _ := ReleaseNote(test.body); note != test.note {
+				t.Errorf("note = %q, want %q", note, test.note)
+			}
+		})
+	}
+}
+
+func TestCommitTrailer(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		note    string
+	}{
+		{
+			name:    "trailer",
+			message: "Add the foo setting (#123)\n\nDetails\n\nRelease-Note: Added the foo setting.\nSigned-off-by: Jane <jane@example.com>",
+			note:    "Added the foo setting.",
+		},
+		{
+			name:    "continued trailer",
+			message: "Add the foo setting (#123)\n\nrelease-note: Added the foo\n  setting.",
+			note:    "Added the foo setting.",
+		},
+		{
+			name:    "trailer outside the last paragraph",
+			message: "Add the foo setting (#123)\n\nRelease-Note: Added the foo setting.\n\nSigned-off-by: Jane <jane@example.com>",
+			note:    "",
+		},
+		{
+			name:    "subject only",
+			message: "Release-Note: Added the foo setting.",
+			note:    "",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if note := CommitTrailer(test.message); note != test.note {
+				t.Errorf("note = %q, want %q", note, test.note)
+			}
+		})
+	}
+}
@@ -0,0 +1,591 @@
+// Package fakegithub implements a fake of the parts of the GitHub REST and
+// GraphQL APIs used by the release notes extractor, serving milestones, issues and pull
+// requests from fixtures. It allows testing the whole tool, and trying config
+// changes, against synthetic data.
+package fakegithub
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Fixtures holds the data served, by owner/repo name
+type Fixtures struct {
+	Repos map[string]*Repo `yaml:"repos" json:"repos"`
+	// RateLimit limits the requests served, like GitHub does, when set
+	RateLimit *RateLimit `yaml:"rate_limit" json:"rate_limit"`
+	// SecondaryRateLimit rejects some of the requests, like GitHub does
+	// when too many are sent at once, when set
+	SecondaryRateLimit *SecondaryRateLimit `yaml:"secondary_rate_limit" json:"secondary_rate_limit"`
+}
+
+// RateLimit is the number of requests served per window, the rest failing
+// with a 403 response until the window resets
+type RateLimit struct {
+	Limit  int           `yaml:"limit" json:"limit"`
+	Window time.Duration `yaml:"window" json:"window"`
+}
+
+// SecondaryRateLimit rejects every Every requests with a 403 response and a
+// Retry-After header of RetryAfter seconds
+type SecondaryRateLimit struct {
+	Every      int `yaml:"every" json:"every"`
+	RetryAfter int `yaml:"retry_after" json:"retry_after"`
+}
+
+// Repo holds the data of a repository
+type Repo struct {
+	Milestones   []Milestone   `yaml:"milestones" json:"milestones"`
+	Issues       []Issue       `yaml:"issues" json:"issues"`
+	PullRequests []PullRequest `yaml:"pull_requests" json:"pull_requests"`
+}
+
+// Milestone is a repository milestone
+type Milestone struct {
+	Number      int        `yaml:"number" json:"number"`
+	Title       string     `yaml:"title" json:"title"`
+	Description string     `yaml:"description" json:"description"`
+	State       string     `yaml:"state" json:"state"` // open (default) or closed
+	CreatedAt   time.Time  `yaml:"created_at" json:"created_at"`
+	DueOn       *time.Time `yaml:"due_on" json:"due_on"`
+}
+
+// Issue is an issue, the milestone is referenced by number
+type Issue struct {
+	Number    int      `yaml:"number" json:"number"`
+	Title     string   `yaml:"title" json:"title"`
+	Body      string   `yaml:"body" json:"body"`
+	State     string   `yaml:"state" json:"state"` // open (default) or closed
+	Labels    []string `yaml:"labels" json:"labels"`
+	Milestone int      `yaml:"milestone" json:"milestone"`
+	// Author is the login of the user who opened the issue
+	Author    string    `yaml:"author" json:"author"`
+	CreatedAt time.Time `yaml:"created_at" json:"created_at"`
+}
+
+// PullRequest is a pull request, listed as an issue too like GitHub does
+type PullRequest struct {
+	Issue     `yaml:",inline"`
+	Files     []string   `yaml:"files" json:"files"`
+	Additions int        `yaml:"additions" json:"additions"`
+	Deletions int        `yaml:"deletions" json:"deletions"`
+	MergedAt  *time.Time `yaml:"merged_at" json:"merged_at"`
+	// MergeCommitMessage is the message of the merge commit, served under a
+	// SHA derived from the PR number
+	MergeCommitMessage string `yaml:"merge_commit_message" json:"merge_commit_message"`
+}
+
+// mergeCommitSHA returns the fake SHA of the merge commit of a PR
+func mergeCommitSHA(number int) string {
+	return fmt.Sprintf("%040d", number)
+}
+
+// LoadFixtures reads the fixtures from a YAML or JSON file
+func LoadFixtures(path string) (*Fixtures, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var fixtures Fixtures
+	if err := yaml.Unmarshal(data, &fixtures); err != nil {
+		return nil, fmt.Errorf("error parsing fixtures %s: %w", path, err)
+	}
+
+	return &fixtures, nil
+}
+
+// defaultPerPage is the page size GitHub uses when none is requested
+const defaultPerPage = 30
+
+// NewHandler returns an http.Handler serving the fixtures under the same
+// paths as https://api.github.com
+func NewHandler(fixtures *Fixtures) http.Handler {
+	s := &server{fixtures: fixtures}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /user", s.handleUser)
+	mux.HandleFunc("GET /rate_limit", s.handleRateLimit)
+	mux.HandleFunc("GET /repos/{owner}/{repo}", s.handleRepo)
+	mux.HandleFunc("GET /repos/{owner}/{repo}/milestones", s.handleMilestones)
+	mux.HandleFunc("GET /repos/{owner}/{repo}/issues", s.handleIssues)
+	mux.HandleFunc("GET /repos/{owner}/{repo}/issues/{number}", s.handleIssue)
+	mux.HandleFunc("GET /repos/{owner}/{repo}/pulls/{number}", s.handlePull)
+	mux.HandleFunc("GET /repos/{owner}/{repo}/pulls/{number}/files", s.handlePullFiles)
+	mux.HandleFunc("GET /repos/{owner}/{repo}/commits/{sha}", s.handleCommit)
+	mux.HandleFunc("GET /search/issues", s.handleSearchIssues)
+	mux.HandleFunc("POST /graphql", s.handleGraphQL)
+
+	handler := conditional(mux)
+	if fixtures.RateLimit != nil {
+		handler = s.limitRate(handler)
+	}
+	if fixtures.SecondaryRateLimit != nil && fixtures.SecondaryRateLimit.Every > 0 {
+		handler = s.limitSecondaryRate(handler)
+	}
+	return handler
+}
+
+type server struct {
+	fixtures *Fixtures
+
+	mu        sync.Mutex
+	used      int
+	resetTime time.Time
+	// requests counts the requests for the secondary rate limit
+	requests int
+}
+
+// limitRate serves the requests within the rate limit, setting the
+// X-RateLimit headers GitHub sends, and fails the rest with a 403 response
+func (s *server) limitRate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limit, remaining, reset := s.takeRequest()
+		w.Header().Set("X-RateLimit-Limit", strconv.Itoa(limit))
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(max(remaining, 0)))
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+		w.Header().Set("X-RateLimit-Resource", "core")
+		if remaining < 0 {
+			writeError(w, http.StatusForbidden, "API rate limit exceeded")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// limitSecondaryRate rejects every Every requests with the response GitHub
+// sends when a secondary rate limit is hit
+func (s *server) limitSecondaryRate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limit := s.fixtures.SecondaryRateLimit
+		s.mu.Lock()
+		s.requests++
+		rejected := s.requests%limit.Every == 0
+		s.mu.Unlock()
+
+		if rejected {
+			w.Header().Set("Retry-After", strconv.Itoa(limit.RetryAfter))
+			writeError(w, http.StatusForbidden, "You have exceeded a secondary rate limit. Please wait a few minutes before you try again.")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// bufferedResponse holds a response to send it after computing its ETag
+type bufferedResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (b *bufferedResponse) Header() http.Header { return b.header }
+
+func (b *bufferedResponse) Write(data []byte) (int, error) { return b.body.Write(data) }
+
+func (b *bufferedResponse) WriteHeader(status int) { b.status = status }
+
+// conditional sets the ETag header of the successful responses, like GitHub
+// does, answering 304 Not Modified to the requests with a matching
+// If-None-Match header
+func conditional(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		buffered := &bufferedResponse{header: w.Header(), status: http.StatusOK}
+		next.ServeHTTP(buffered, r)
+
+		if buffered.status == http.StatusOK {
+			sum := sha256.Sum256(buffered.body.Bytes())
+			etag := `"` + hex.EncodeToString(sum[:]) + `"`
+			w.Header().Set("ETag", etag)
+			if r.Header.Get("If-None-Match") == etag {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+		}
+
+		w.WriteHeader(buffered.status)
+		w.Write(buffered.body.Bytes())
+	})
+}
+
+// takeRequest counts a request against the rate limit, returning the limit,
+// the remaining requests, negative when exceeded, and the reset time
+func (s *server) takeRequest() (int, int, time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	limit := s.fixtures.RateLimit
+	if now := time.Now(); now.After(s.resetTime) {
+		s.used = 0
+		s.resetTime = now.Add(limit.Window)
+	}
+	s.used++
+	return limit.Limit, limit.Limit - s.used, s.resetTime
+}
+
+// repo returns the fixtures of the repository in the request path, writing a
+// 404 response when there are none
+func (s *server) repo(w http.ResponseWriter, r *http.Request) *Repo {
+	repo := s.fixtures.Repos[r.PathValue("owner")+"/"+r.PathValue("repo")]
+	if repo == nil {
+		writeError(w, http.StatusNotFound, "Not Found")
+	}
+	return repo
+}
+
+// pullRequest returns the pull request in the request path, writing a 404
+// response when it doesn't exist
+func (s *server) pullRequest(w http.ResponseWriter, r *http.Request) *PullRequest {
+	repo := s.repo(w, r)
+	if repo == nil {
+		return nil
+	}
+
+	number, _ := strconv.Atoi(r.PathValue("number"))
+	for i := range repo.PullRequests {
+		if repo.PullRequests[i].Number == number {
+			return &repo.PullRequests[i]
+		}
+	}
+
+	writeError(w, http.StatusNotFound, "Not Found")
+	return nil
+}
+
+func (s *server) handleUser(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, map[string]string{"login": "fakegithub", "name": "Fake GitHub"})
+}
+
+func (s *server) handleRateLimit(w http.ResponseWriter, r *http.Request) {
+	core := map[string]int64{"limit": 5000, "remaining": 5000, "reset": time.Now().Add(time.Hour).Unix()}
+	writeJSON(w, map[string]any{"resources": map[string]any{"core": core}})
+}
+
+func (s *server) handleRepo(w http.ResponseWriter, r *http.Request) {
+	if s.repo(w, r) == nil {
+		return
+	}
+
+	writeJSON(w, map[string]any{
+		"full_name":   r.PathValue("owner") + "/" + r.PathValue("repo"),
+		"permissions": map[string]bool{"pull": true, "push": true},
+	})
+}
+
+func (s *server) handleMilestones(w http.ResponseWriter, r *http.Request) {
+	repo := s.repo(w, r)
+	if repo == nil {
+		return
+	}
+
+	var result []any
+	for _, milestone := range repo.Milestones {
+		if matchState(r.URL.Query().Get("state"), milestone.State) {
+			result = append(result, milestoneJSON(milestone))
+		}
+	}
+
+	writePage(w, r, result)
+}
+
+func (s *server) handleIssues(w http.ResponseWriter, r *http.Request) {
+	repo := s.repo(w, r)
+	if repo == nil {
+		return
+	}
+
+	query := r.URL.Query()
+	var labels []string
+	if query.Get("labels") != "" {
+		labels = strings.Split(query.Get("labels"), ",")
+	}
+
+	var result []any
+	for _, issue := range repo.Issues {
+		if matchIssue(issue, query.Get("state"), query.Get("milestone"), labels) {
+			result = append(result, issueJSON(repo, issue))
+		}
+	}
+	for _, pr := range repo.PullRequests {
+		if matchIssue(pr.Issue, query.Get("state"), query.Get("milestone"), labels) {
+			result = append(result, prIssueJSON(repo, pr))
+		}
+	}
+
+	writePage(w, r, result)
+}
+
+func (s *server) handleIssue(w http.ResponseWriter, r *http.Request) {
+	repo := s.repo(w, r)
+	if repo == nil {
+		return
+	}
+
+	number, _ := strconv.Atoi(r.PathValue("number"))
+	for _, issue := range repo.Issues {
+		if issue.Number == number {
+			writeJSON(w, issueJSON(repo, issue))
+			return
+		}
+	}
+	for _, pr := range repo.PullRequests {
+		if pr.Number == number {
+			writeJSON(w, prIssueJSON(repo, pr))
+			return
+		}
+	}
+
+	writeError(w, http.StatusNotFound, "Not Found")
+}
+
+func (s *server) handlePull(w http.ResponseWriter, r *http.Request) {
+	pr := s.pullRequest(w, r)
+	if pr == nil {
+		return
+	}
+
+	writeJSON(w, map[string]any{
+		"number":           pr.Number,
+		"title":            pr.Title,
+		"body":             pr.Body,
+		"state":            stateOrOpen(pr.State),
+		"additions":        pr.Additions,
+		"deletions":        pr.Deletions,
+		"merged_at":        pr.MergedAt,
+		"merge_commit_sha": mergeCommitSHA(pr.Number),
+	})
+}
+
+func (s *server) handleCommit(w http.ResponseWriter, r *http.Request) {
+	repo := s.repo(w, r)
+	if repo == nil {
+		return
+	}
+
+	for _, pr := range repo.PullRequests {
+		if mergeCommitSHA(pr.Number) == r.PathValue("sha") {
+			writeJSON(w, map[string]any{
+				"sha":    mergeCommitSHA(pr.Number),
+				"commit": map[string]string{"message": pr.MergeCommitMessage},
+			})
+			return
+		}
+	}
+
+	writeError(w, http.StatusNotFound, "No commit found for SHA: "+r.PathValue("sha"))
+}
+
+func (s *server) handlePullFiles(w http.ResponseWriter, r *http.Request) {
+	pr := s.pullRequest(w, r)
+	if pr == nil {
+		return
+	}
+
+	var result []any
+	for _, file := range pr.Files {
+		result = append(result, map[string]string{"filename": file})
+	}
+
+	writePage(w, r, result)
+}
+
+// matchIssue applies the filters of the issues API, where the milestone is
+// a number, "*" for any or "none"
+func matchIssue(issue Issue, state string, milestone string, labels []string) bool {
+	if !matchState(state, issue.State) {
+		return false
+	}
+
+	switch milestone {
+	case "":
+	case "*":
+		if issue.Milestone == 0 {
+			return false
+		}
+	case "none":
+		if issue.Milestone != 0 {
+			return false
+		}
+	default:
+		if strconv.Itoa(issue.Milestone) != milestone {
+			return false
+		}
+	}
+
+	for _, label := range labels {
+		found := false
+		for _, issueLabel := range issue.Labels {
+			if issueLabel == label {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matchState applies a state filter, which defaults to open like in GitHub
+func matchState(filter string, state string) bool {
+	if filter == "all" {
+		return true
+	}
+	if filter == "" {
+		filter = "open"
+	}
+	return filter == stateOrOpen(state)
+}
+
+func stateOrOpen(state string) string {
+	if state == "" {
+		return "open"
+	}
+	return state
+}
+
+func milestoneJSON(milestone Milestone) map[string]any {
+	return map[string]any{
+		"number":      milestone.Number,
+		"title":       milestone.Title,
+		"description": milestone.Description,
+		"state":       stateOrOpen(milestone.State),
+		"created_at":  milestone.CreatedAt,
+		"due_on":      milestone.DueOn,
+	}
+}
+
+func issueJSON(repo *Repo, issue Issue) map[string]any {
+	labels := make([]map[string]string, 0, len(issue.Labels))
+	for _, label := range issue.Labels {
+		labels = append(labels, map[string]string{"name": label})
+	}
+
+	result := map[string]any{
+		"number":     issue.Number,
+		"title":      issue.Title,
+		"body":       issue.Body,
+		"state":      stateOrOpen(issue.State),
+		"labels":     labels,
+		"milestone":  nil,
+		"user":       map[string]string{"login": issue.Author},
+		"created_at": issue.CreatedAt,
+	}
+	for _, milestone := range repo.Milestones {
+		if milestone.Number == issue.Milestone {
+			result["milestone"] = milestoneJSON(milestone)
+		}
+	}
+
+	return result
+}
+
+// prIssueJSON returns a PR as listed by the issues API, which marks PRs with
+// a pull_request object
+func prIssueJSON(repo *Repo, pr PullRequest) map[string]any {
+	result := issueJSON(repo, pr.Issue)
+	result["pull_request"] = map[string]any{"merged_at": pr.MergedAt}
+	return result
+}
+
+// writePage writes a page of a list, honoring the page and per_page
+// parameters and adding a Link header to the next page like GitHub does
+func writePage(w http.ResponseWriter, r *http.Request, items []any) {
+	query := r.URL.Query()
+	perPage, err := strconv.Atoi(query.Get("per_page"))
+	if err != nil || perPage <= 0 {
+		perPage = defaultPerPage
+	}
+	page, err := strconv.Atoi(query.Get("page"))
+	if err != nil || page <= 0 {
+		page = 1
+	}
+
+	start := min((page-1)*perPage, len(items))
+	end := min(start+perPage, len(items))
+	if end < len(items) {
+		next := *r.URL
+		query.Set("page", strconv.Itoa(page+1))
+		next.RawQuery = query.Encode()
+		w.Header().Set("Link", fmt.Sprintf(`<http://%s%s>; rel="next"`, r.Host, next.RequestURI()))
+	}
+
+	result := items[start:end]
+	if result == nil {
+		result = []any{}
+	}
+	writeJSON(w, result)
+}
+
+// handleSearchIssues supports the searches of merged pull requests of a
+// repository, with the repo:, author:, is:pr, is:merged and created:<
+// qualifiers of the q parameter
+func (s *server) handleSearchIssues(w http.ResponseWriter, r *http.Request) {
+	var repoName, author string
+	var createdBefore time.Time
+	merged := false
+	for _, term := range strings.Fields(r.URL.Query().Get("q")) {
+		qualifier, value, _ := strings.Cut(term, ":")
+		switch {
+		case qualifier == "repo":
+			repoName = value
+		case qualifier == "author":
+			author = value
+		case qualifier == "is" && value == "merged":
+			merged = true
+		case qualifier == "created" && strings.HasPrefix(value, "<"):
+			t, err := time.Parse(time.RFC3339, strings.TrimPrefix(value, "<"))
+			if err != nil {
+				writeError(w, http.StatusUnprocessableEntity, "Validation Failed")
+				return
+			}
+			createdBefore = t
+		}
+	}
+
+	repo := s.fixtures.Repos[repoName]
+	if repo == nil {
+		writeError(w, http.StatusUnprocessableEntity, "Validation Failed")
+		return
+	}
+
+	items := []any{}
+	for _, pr := range repo.PullRequests {
+		if author != "" && !strings.EqualFold(pr.Author, author) {
+			continue
+		}
+		if merged && pr.MergedAt == nil {
+			continue
+		}
+		if !createdBefore.IsZero() && !pr.CreatedAt.Before(createdBefore) {
+			continue
+		}
+		items = append(items, prIssueJSON(repo, pr))
+	}
+
+	writeJSON(w, map[string]any{"total_count": len(items), "incomplete_results": false, "items": items})
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"message": message})
+}
@@ -0,0 +1,141 @@
+package fakegithub
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// graphqlPageSize is the page size of the connections, the first argument
+// of the queries isn't parsed
+const graphqlPageSize = 100
+
+// handleGraphQL supports the two queries of the release notes extractor: the
+// open milestones of a repository, and the pull requests of a milestone with
+// any of the labels. The query is recognized by the connection it asks for,
+// the arguments are taken from the variables.
+func (s *server) handleGraphQL(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Query     string `json:"query"`
+		Variables struct {
+			Owner  string   `json:"owner"`
+			Name   string   `json:"name"`
+			Number int      `json:"number"`
+			Labels []string `json:"labels"`
+			Cursor string   `json:"cursor"`
+		} `json:"variables"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, "Problems parsing JSON")
+		return
+	}
+	variables := request.Variables
+
+	repoName := variables.Owner + "/" + variables.Name
+	repo := s.fixtures.Repos[repoName]
+	if repo == nil {
+		writeGraphQLError(w, "Could not resolve to a Repository with the name '"+repoName+"'.")
+		return
+	}
+
+	var nodes []any
+	switch {
+	case strings.Contains(request.Query, "milestones("):
+		for _, milestone := range repo.Milestones {
+			if stateOrOpen(milestone.State) == "open" {
+				nodes = append(nodes, map[string]any{
+					"number":      milestone.Number,
+					"title":       milestone.Title,
+					"description": milestone.Description,
+					"createdAt":   milestone.CreatedAt,
+					"dueOn":       milestone.DueOn,
+				})
+			}
+		}
+		writeJSON(w, map[string]any{"data": map[string]any{"repository": map[string]any{
+			"milestones": graphqlConnection(nodes, variables.Cursor),
+		}}})
+
+	case strings.Contains(request.Query, "milestone(") && strings.Contains(request.Query, "pullRequests("):
+		found := slices.ContainsFunc(repo.Milestones, func(milestone Milestone) bool {
+			return milestone.Number == variables.Number
+		})
+		if !found {
+			writeJSON(w, map[string]any{"data": map[string]any{"repository": map[string]any{"milestone": nil}}})
+			return
+		}
+
+		for _, pr := range repo.PullRequests {
+			if pr.Milestone != variables.Number {
+				continue
+			}
+			if len(variables.Labels) > 0 && !slices.ContainsFunc(pr.Labels, func(label string) bool {
+				return slices.Contains(variables.Labels, label)
+			}) {
+				continue
+			}
+			nodes = append(nodes, graphqlPullRequestJSON(pr))
+		}
+		writeJSON(w, map[string]any{"data": map[string]any{"repository": map[string]any{
+			"milestone": map[string]any{"pullRequests": graphqlConnection(nodes, variables.Cursor)},
+		}}})
+
+	default:
+		writeGraphQLError(w, "Unsupported query")
+	}
+}
+
+// graphqlConnection returns the page of the nodes after the cursor, which is
+// the offset of the page
+func graphqlConnection(nodes []any, cursor string) map[string]any {
+	start, _ := strconv.Atoi(cursor)
+	start = min(max(start, 0), len(nodes))
+	end := min(start+graphqlPageSize, len(nodes))
+
+	page := nodes[start:end]
+	if page == nil {
+		page = []any{}
+	}
+	return map[string]any{
+		"pageInfo": map[string]any{"hasNextPage": end < len(nodes), "endCursor": strconv.Itoa(end)},
+		"nodes":    page,
+	}
+}
+
+func graphqlPullRequestJSON(pr PullRequest) map[string]any {
+	labels := make([]map[string]string, 0, len(pr.Labels))
+	for _, label := range pr.Labels {
+		labels = append(labels, map[string]string{"name": label})
+	}
+
+	var author any
+	if pr.Author != "" {
+		author = map[string]string{"login": pr.Author}
+	}
+
+	state := strings.ToUpper(stateOrOpen(pr.State))
+	if pr.MergedAt != nil {
+		state = "MERGED"
+	}
+
+	return map[string]any{
+		"number":    pr.Number,
+		"title":     pr.Title,
+		"body":      pr.Body,
+		"state":     state,
+		"createdAt": pr.CreatedAt,
+		"mergedAt":  pr.MergedAt,
+		"additions": pr.Additions,
+		"deletions": pr.Deletions,
+		"author":    author,
+		"labels":    map[string]any{"nodes": labels},
+	}
+}
+
+// writeGraphQLError writes a GraphQL error, which GitHub reports with a 200
+// response
+func writeGraphQLError(w http.ResponseWriter, message string) {
+	writeJSON(w, map[string]any{"data": nil, "errors": []map[string]string{{"message": message}}})
+}
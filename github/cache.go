@@ -0,0 +1,104 @@
+package github
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// cacheEntry is a response stored on disk, revalidated with a conditional
+// request before using it. GitHub answers 304 Not Modified when it didn't
+// change, which doesn't count against the rate limit.
+type cacheEntry struct {
+	URL          string      `json:"url"`
+	ETag         string      `json:"etag,omitempty"`
+	LastModified string      `json:"last_modified,omitempty"`
+	Header       http.Header `json:"header"`
+	Body         []byte      `json:"body"`
+}
+
+// cacheable reports whether the response of a request is cached
+func (c *Client) cacheable(method string) bool {
+	return c.config.CacheDir != "" && method == http.MethodGet
+}
+
+// cachePath returns the file of the cached response of a URL, named after
+// its cacheKey
+func (c *Client) cachePath(url string, credentials string) string {
+	return filepath.Join(c.config.CacheDir, cacheKey(url, credentials)+".json")
+}
+
+// cacheKey returns the key of the cached response of a URL. It is salted
+// with the credentials of the request, as responses depend on what the token
+// can see, so switching tokens never serves the responses cached for another
+// identity.
+func cacheKey(url string, credentials string) string {
+	sum := sha256.Sum256([]byte(credentials + "\n" + url))
+	return hex.EncodeToString(sum[:])
+}
+
+// loadCacheEntry returns the cached response of a URL, or nil if there is
+// none
+func (c *Client) loadCacheEntry(url string, credentials string) *cacheEntry {
+	data, err := os.ReadFile(c.cachePath(url, credentials))
+	if err != nil {
+		return nil
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.URL != url {
+		c.logger.Debug("Ignoring invalid cache entry", "url", url, "error", err)
+		return nil
+	}
+	return &entry
+}
+
+// storeCacheEntry caches a response that can be revalidated, failing
+// silently as the cache is only an optimization
+func (c *Client) storeCacheEntry(url string, credentials string, header http.Header, body []byte) {
+	entry := cacheEntry{URL: url, ETag: header.Get("ETag"), LastModified: header.Get("Last-Modified"), Header: header, Body: body}
+	if entry.ETag == "" && entry.LastModified == "" {
+		return
+	}
+
+	path := c.cachePath(url, credentials)
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		c.logger.Debug("Can't create the cache directory", "error", err)
+		return
+	}
+
+	// Concurrent requests for the same URL must not see half-written files
+	tmp, err := os.CreateTemp(filepath.Dir(path), "entry-*")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		c.logger.Debug("Can't write the cache entry", "url", url, "error", err)
+	}
+}
+
+// setConditionalHeaders makes the request conditional on the cached
+// response having changed
+func (e *cacheEntry) setConditionalHeaders(req *http.Request) {
+	if e.ETag != "" {
+		req.Header.Set("If-None-Match", e.ETag)
+	}
+	if e.LastModified != "" {
+		req.Header.Set("If-Modified-Since", e.LastModified)
+	}
+}
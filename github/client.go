@@ -0,0 +1,345 @@
+package github
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// APIURL is the base URL of the GitHub REST API
+const APIURL = "https://api.github.com"
+
+// maxRateLimitWaits is how many times a request waits for the rate limit to
+// reset before failing
+const maxRateLimitWaits = 2
+
+// rateLimitProgressInterval is how often the wait for the rate limit to
+// reset reports the time left
+const rateLimitProgressInterval = 30 * time.Second
+
+// maxRecordedCalls is the number of recent API calls kept for the
+// diagnostics of a crash
+const maxRecordedCalls = 50
+
+// Config configures a Client
+type Config struct {
+	// BaseURL replaces APIURL in the request URLs, e.g. for GitHub
+	// Enterprise or a fake server
+	BaseURL string
+	// UserAgent identifies the client in the requests
+	UserAgent string
+	// Headers are extra headers sent with every request, as required by
+	// some proxies and GitHub Enterprise setups
+	Headers map[string]string
+	// Retry configures the retries of the requests failing with transient
+	// errors
+	Retry RetryPolicy
+	// CacheDir is where the GET responses are cached, revalidated with
+	// conditional requests before using them. Responses aren't cached when
+	// empty.
+	CacheDir string
+	// Auth returns the authentication header for a request URL, for the
+	// clients also requesting the APIs of other forges. The token is sent
+	// as a bearer token when nil.
+	Auth func(url string) (name string, value string)
+	// Remediation returns the steps to fix the problem of an error
+	// response, if known
+	Remediation func(e *APIError) []string
+}
+
+// APICall is a request made by the client, recorded for the diagnostics of
+// a crash
+type APICall struct {
+	Time     time.Time
+	Method   string
+	URL      string
+	Status   int // Zero when the request failed
+	Duration time.Duration
+	Err      string
+}
+
+// Client makes authenticated requests to the GitHub REST API, retrying the
+// transient errors, waiting for the rate limit to reset when exhausted and
+// caching the responses. It is safe for concurrent use.
+type Client struct {
+	token  string
+	config Config
+	logger Logger
+
+	rateLimitMu sync.Mutex
+	rateLimit   *RateLimitStatus
+	// rateLimitWaitMu makes the concurrent requests hitting the rate limit
+	// wait for the reset together, reporting the progress once
+	rateLimitWaitMu sync.Mutex
+
+	callsMu sync.Mutex
+	calls   []APICall
+}
+
+// NewClient returns a client authenticating with token, which can be empty
+// for unauthenticated requests. The messages are logged to stderr when logger
+// is nil.
+func NewClient(token string, config Config, logger Logger) *Client {
+	c := &Client{token: token, config: config}
+	c.SetLogger(logger)
+	return c
+}
+
+// SetLogger routes the diagnostic messages of the client to logger, or to
+// stderr when nil. It must be called before making requests.
+func (c *Client) SetLogger(logger Logger) {
+	if logger == nil {
+		logger = &ConsoleLogger{Out: os.Stderr}
+	}
+	c.logger = logger
+}
+
+// GetJSON performs a GET request and decodes the JSON response into v
+func (c *Client) GetJSON(url string, v interface{}) error {
+	return c.DoJSON("GET", url, nil, v)
+}
+
+// GetJSONWithHeader works like GetJSON, also returning the response headers
+func (c *Client) GetJSONWithHeader(url string, v interface{}) (http.Header, error) {
+	return c.Do("GET", url, "", nil, v)
+}
+
+// GetAllPages fetches every page of a list endpoint, following the Link
+// headers, and returns all the items. Pages of 100 items are requested unless
+// the URL sets per_page.
+func GetAllPages[T any](c *Client, url string) ([]T, error) {
+	if !strings.Contains(url, "per_page=") {
+		separator := "?"
+		if strings.Contains(url, "?") {
+			separator = "&"
+		}
+		url += separator + "per_page=100"
+	}
+
+	var items []T
+	for url != "" {
+		var page []T
+		header, err := c.GetJSONWithHeader(url, &page)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, page...)
+
+		url = NextPageURL(header)
+	}
+
+	return items, nil
+}
+
+// DoJSON performs a request, sending body encoded as JSON when not nil, and
+// decodes the JSON response into v when not nil
+func (c *Client) DoJSON(method string, url string, body interface{}, v interface{}) error {
+	if body == nil {
+		_, err := c.Do(method, url, "", nil, v)
+		return err
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.Do(method, url, "application/json", bytes.NewReader(data), v)
+	return err
+}
+
+// Do performs a request with a raw body of the given content type, decodes
+// the JSON response into v when not nil and returns the response headers.
+// Network errors and 5xx responses are retried following the retry policy.
+func (c *Client) Do(method string, url string, contentType string, body io.Reader, v interface{}) (http.Header, error) {
+	if c.config.BaseURL != "" && strings.HasPrefix(url, APIURL) {
+		url = strings.TrimSuffix(c.config.BaseURL, "/") + strings.TrimPrefix(url, APIURL)
+	}
+
+	// The body is sent again on every attempt
+	var data []byte
+	if body != nil {
+		var err error
+		if data, err = io.ReadAll(body); err != nil {
+			return nil, err
+		}
+	}
+
+	policy := c.config.Retry
+	attempt, rateLimitWaits := 1, 0
+	for {
+		header, retryable, err := c.doOnce(method, url, contentType, data, v)
+
+		// Requests rejected by the rate limit weren't processed, so even
+		// POST requests are sent again once it resets
+		if reset, ok := RateLimitReset(err); ok && rateLimitWaits < maxRateLimitWaits {
+			rateLimitWaits++
+			// The reset time has a resolution of seconds
+			c.waitForRateLimit(reset.Add(time.Second), "GitHub API rate limit exhausted, waiting for it to reset")
+			continue
+		}
+		if until, ok := RetryAfter(err); ok && rateLimitWaits < maxRateLimitWaits {
+			rateLimitWaits++
+			c.waitForRateLimit(until, "GitHub API secondary rate limit hit, waiting to retry")
+			continue
+		}
+
+		if err == nil || !retryable || !RetryableMethod(method, url) || attempt >= policy.Attempts {
+			return header, err
+		}
+
+		delay := policy.Delay(attempt)
+		c.logger.Warn("GitHub API request failed, retrying", "method", method, "url", url, "attempt", attempt, "delay", delay.Round(time.Millisecond), "error", err)
+		time.Sleep(delay)
+		attempt++
+	}
+}
+
+// doOnce performs a single attempt of a request for Do, also reporting
+// whether the error is transient and the request can be retried
+func (c *Client) doOnce(method string, url string, contentType string, data []byte, v interface{}) (http.Header, bool, error) {
+	var body io.Reader
+	if data != nil {
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return nil, false, err
+	}
+
+	authName, authValue := c.authHeader(url)
+	if authValue != "" {
+		req.Header.Set(authName, authValue)
+	}
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	req.Header.Set("User-Agent", c.config.UserAgent)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	for name, value := range c.config.Headers {
+		req.Header.Set(name, value)
+	}
+
+	// Revalidate the cached response instead of downloading it again
+	var cached *cacheEntry
+	if c.cacheable(method) {
+		if cached = c.loadCacheEntry(url, authValue); cached != nil {
+			cached.setConditionalHeaders(req)
+		}
+	}
+
+	start := time.Now()
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		c.recordCall(APICall{Time: start, Method: method, URL: url, Duration: time.Since(start), Err: err.Error()})
+		c.logger.Debug("GitHub API request failed", "method", method, "url", url, "error", err)
+		return nil, true, err
+	}
+	defer resp.Body.Close()
+	c.recordCall(APICall{Time: start, Method: method, URL: url, Status: resp.StatusCode, Duration: time.Since(start)})
+	c.recordRateLimit(resp.Header)
+	c.logger.Debug("GitHub API request", "method", method, "url", url, "status", resp.StatusCode, "duration", time.Since(start).Round(time.Millisecond))
+
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		if v == nil {
+			return cached.Header, false, nil
+		}
+		return cached.Header, false, json.Unmarshal(cached.Body, v)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		// Read error response body for more details
+		errorBody := make([]byte, 1024)
+		n, _ := resp.Body.Read(errorBody)
+		apiErr := &APIError{StatusCode: resp.StatusCode, URL: url, Body: string(errorBody[:n]), Header: resp.Header}
+		if c.config.Remediation != nil {
+			apiErr.Steps = c.config.Remediation(apiErr)
+		}
+		return resp.Header, RetryableStatus(resp.StatusCode), apiErr
+	}
+
+	if v == nil {
+		return resp.Header, false, nil
+	}
+
+	if c.cacheable(method) {
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return resp.Header, true, err
+		}
+		c.storeCacheEntry(url, authValue, resp.Header, data)
+		return resp.Header, false, json.Unmarshal(data, v)
+	}
+
+	return resp.Header, false, json.NewDecoder(resp.Body).Decode(v)
+}
+
+// authHeader returns the authentication header for a request URL, or an
+// empty value when there are no credentials
+func (c *Client) authHeader(url string) (string, string) {
+	if c.config.Auth != nil {
+		return c.config.Auth(url)
+	}
+	if c.token == "" {
+		return "Authorization", ""
+	}
+	return "Authorization", "Bearer " + c.token
+}
+
+// recordCall keeps the call in the log of recent API calls
+func (c *Client) recordCall(call APICall) {
+	c.callsMu.Lock()
+	defer c.callsMu.Unlock()
+
+	c.calls = append(c.calls, call)
+	if len(c.calls) > maxRecordedCalls {
+		c.calls = c.calls[len(c.calls)-maxRecordedCalls:]
+	}
+}
+
+// RecentCalls returns the last requests made by the client, oldest first
+func (c *Client) RecentCalls() []APICall {
+	c.callsMu.Lock()
+	defer c.callsMu.Unlock()
+
+	return append([]APICall(nil), c.calls...)
+}
+
+// recordRateLimit keeps the rate limit reported in the headers of a response
+func (c *Client) recordRateLimit(header http.Header) {
+	status, ok := ParseRateLimit(header)
+	if !ok {
+		return
+	}
+
+	c.rateLimitMu.Lock()
+	defer c.rateLimitMu.Unlock()
+	c.rateLimit = &status
+}
+
+// RateLimit returns the rate limit reported by the last response, or nil if
+// no request was made
+func (c *Client) RateLimit() *RateLimitStatus {
+	c.rateLimitMu.Lock()
+	defer c.rateLimitMu.Unlock()
+
+	return c.rateLimit
+}
+
+// waitForRateLimit pauses until the rate limit resets, or the time to retry
+// after hitting a secondary rate limit, reporting the time left periodically
+func (c *Client) waitForRateLimit(until time.Time, message string) {
+	c.rateLimitWaitMu.Lock()
+	defer c.rateLimitWaitMu.Unlock()
+
+	for left := time.Until(until); left > 0; left = time.Until(until) {
+		c.logger.Warn(message, "until", until.Format("15:04:05"), "left", left.Round(time.Second))
+		time.Sleep(min(left, rateLimitProgressInterval))
+	}
+}
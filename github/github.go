@@ -0,0 +1,72 @@
+// Package github holds the GitHub REST API pieces of the release notes
+// extractor that don't depend on its flags or configuration: the client, the
+// errors returned for the failed requests, the retry policy, the rate limit
+// headers and the pagination.
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// APIError is returned when the GitHub API responds with an error status
+type APIError struct {
+	StatusCode int
+	URL        string
+	Body       string
+	Header     http.Header
+	// Steps to fix the problem, shown instead of the raw response when set
+	Steps []string
+}
+
+func (e *APIError) Error() string {
+	if len(e.Steps) == 0 {
+		return fmt.Sprintf("API responded with code: %d for URL %s - Response: %s", e.StatusCode, e.URL, e.Body)
+	}
+
+	// Replace the raw response with steps to fix the problem
+	message := fmt.Sprintf("API responded with code: %d for URL %s: %s", e.StatusCode, e.URL, e.Message())
+	for _, step := range e.Steps {
+		message += "\n  - " + step
+	}
+	return message
+}
+
+// Message returns the error message of the API response, falling back to
+// the raw body when it is not a JSON error
+func (e *APIError) Message() string {
+	var body struct {
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal([]byte(e.Body), &body); err == nil && body.Message != "" {
+		return body.Message
+	}
+	return strings.TrimSpace(e.Body)
+}
+
+// SSOAuthorizationURL returns the URL to authorize the token for an
+// organization enforcing SAML SSO, found in the X-GitHub-SSO header as
+// "required; url=https://github.com/orgs/...", or an empty string
+func SSOAuthorizationURL(header http.Header) string {
+	for _, part := range strings.Split(header.Get("X-GitHub-SSO"), ";") {
+		if url, ok := strings.CutPrefix(strings.TrimSpace(part), "url="); ok {
+			return url
+		}
+	}
+	return ""
+}
+
+// linkNextRegexp matches the URL of the next page in a Link header
+var linkNextRegexp = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// NextPageURL returns the URL of the next page of a list from the Link
+// header of a response, or an empty string for the last page
+func NextPageURL(header http.Header) string {
+	if matches := linkNextRegexp.FindStringSubmatch(header.Get("Link")); matches != nil {
+		return matches[1]
+	}
+	return ""
+}
@@ -0,0 +1,67 @@
+package github
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Logger receives the diagnostic messages of the client. The arguments after
+// the message are alternating keys and values, so a *slog.Logger can be used
+// directly.
+type Logger interface {
+	Debug(msg string, args ...any)
+	Info(msg string, args ...any)
+	Warn(msg string, args ...any)
+	Error(msg string, args ...any)
+}
+
+// ConsoleLogger is the default Logger, writing human-readable lines to Out.
+// Debug messages are only written when Verbose is set.
+type ConsoleLogger struct {
+	Out     io.Writer
+	Verbose bool
+}
+
+func (l *ConsoleLogger) Debug(msg string, args ...any) {
+	if l.Verbose {
+		l.write("Debug: ", msg, args)
+	}
+}
+
+func (l *ConsoleLogger) Info(msg string, args ...any) {
+	l.write("", msg, args)
+}
+
+func (l *ConsoleLogger) Warn(msg string, args ...any) {
+	l.write("Warning: ", msg, args)
+}
+
+func (l *ConsoleLogger) Error(msg string, args ...any) {
+	l.write("", msg, args)
+}
+
+// write formats the message followed by its key=value pairs, with an "error"
+// value, if any, appended after a colon like the usual error wrapping
+func (l *ConsoleLogger) write(prefix string, msg string, args []any) {
+	var line strings.Builder
+	line.WriteString(prefix + msg)
+
+	var errValue error
+	for i := 0; i < len(args); i += 2 {
+		if i+1 == len(args) {
+			fmt.Fprintf(&line, " %v", args[i])
+			break
+		}
+		if err, ok := args[i+1].(error); ok && args[i] == "error" {
+			errValue = err
+			continue
+		}
+		fmt.Fprintf(&line, " %v=%v", args[i], args[i+1])
+	}
+	if errValue != nil {
+		line.WriteString(": " + errValue.Error())
+	}
+
+	fmt.Fprintln(l.Out, line.String())
+}
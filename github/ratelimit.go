@@ -0,0 +1,86 @@
+package github
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SecondaryRateLimitWait is how long to wait after hitting a secondary rate
+// limit without a Retry-After header, as recommended by GitHub
+const SecondaryRateLimitWait = time.Minute
+
+// RateLimitStatus is the rate limit reported by a GitHub API response
+type RateLimitStatus struct {
+	Resource  string
+	Limit     int
+	Remaining int
+	Reset     time.Time
+}
+
+// ParseRateLimit returns the rate limit reported in the headers of a
+// response, if any
+func ParseRateLimit(header http.Header) (RateLimitStatus, bool) {
+	remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return RateLimitStatus{}, false
+	}
+	limit, _ := strconv.Atoi(header.Get("X-RateLimit-Limit"))
+	reset, _ := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64)
+
+	return RateLimitStatus{
+		Resource:  header.Get("X-RateLimit-Resource"),
+		Limit:     limit,
+		Remaining: remaining,
+		Reset:     time.Unix(reset, 0),
+	}, true
+}
+
+// RateLimitReset returns when the rate limit resets if the error is a
+// response rejected because the rate limit is exhausted
+func RateLimitReset(err error) (time.Time, bool) {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Header == nil {
+		return time.Time{}, false
+	}
+	if apiErr.StatusCode != http.StatusForbidden && apiErr.StatusCode != http.StatusTooManyRequests {
+		return time.Time{}, false
+	}
+	if apiErr.Header.Get("X-RateLimit-Remaining") != "0" {
+		return time.Time{}, false
+	}
+
+	reset, err := strconv.ParseInt(apiErr.Header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return time.Now().Add(time.Minute), true
+	}
+	return time.Unix(reset, 0), true
+}
+
+// RetryAfter returns when to send again a request rejected by a secondary
+// rate limit, e.g. for too many concurrent requests, from its Retry-After
+// header, in seconds or as a date
+func RetryAfter(err error) (time.Time, bool) {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Header == nil {
+		return time.Time{}, false
+	}
+	if apiErr.StatusCode != http.StatusForbidden && apiErr.StatusCode != http.StatusTooManyRequests {
+		return time.Time{}, false
+	}
+
+	if value := apiErr.Header.Get("Retry-After"); value != "" {
+		if seconds, err := strconv.Atoi(value); err == nil {
+			return time.Now().Add(time.Duration(seconds) * time.Second), true
+		}
+		if date, err := http.ParseTime(value); err == nil {
+			return date, true
+		}
+	}
+	if strings.Contains(strings.ToLower(apiErr.Body), "secondary rate limit") {
+		return time.Now().Add(SecondaryRateLimitWait), true
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,61 @@
+package github
+
+import (
+	"math/rand/v2"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// RetryPolicy configures how the requests to the GitHub API failing with a
+// network error or a 5xx response are retried
+type RetryPolicy struct {
+	// Attempts is the total number of attempts of a request, 1 disables
+	// the retries
+	Attempts int `yaml:"attempts"`
+	// Backoff is the delay before the first retry, doubled on every retry
+	// up to MaxBackoff
+	Backoff    time.Duration `yaml:"backoff"`
+	MaxBackoff time.Duration `yaml:"max_backoff"`
+	// Jitter is the fraction of the delay randomly added or removed, so
+	// concurrent requests don't retry in lockstep
+	Jitter float64 `yaml:"jitter"`
+}
+
+// DefaultRetryPolicy is the retry policy used when the configuration doesn't
+// define one
+var DefaultRetryPolicy = RetryPolicy{
+	Attempts:   4,
+	Backoff:    time.Second,
+	MaxBackoff: 30 * time.Second,
+	Jitter:     0.2,
+}
+
+// Delay returns how long to wait before the given retry, starting at 1
+func (p RetryPolicy) Delay(retry int) time.Duration {
+	delay := p.Backoff
+	for i := 1; i < retry && delay < p.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+
+	if p.Jitter > 0 {
+		delay += time.Duration(float64(delay) * p.Jitter * (2*rand.Float64() - 1))
+	}
+	return delay
+}
+
+// RetryableMethod reports whether requests with the method to the URL can be
+// retried safely. POST requests aren't, as they may have created a release or
+// a post before failing, except for the GraphQL queries.
+func RetryableMethod(method string, url string) bool {
+	return method != http.MethodPost || strings.HasSuffix(url, "/graphql")
+}
+
+// RetryableStatus reports whether a response status is a transient server
+// error worth retrying
+func RetryableStatus(status int) bool {
+	return status >= 500 && status <= 599
+}
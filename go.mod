@@ -2,7 +2,11 @@ module github.com/jespino/github-mm-release-notes
 
 go 1.24.0
 
-require github.com/anthropics/anthropic-sdk-go v0.2.0-beta.3
+require (
+	github.com/anthropics/anthropic-sdk-go v0.2.0-beta.3
+	golang.org/x/sync v0.13.0
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require (
 	github.com/Azure/azure-sdk-for-go/sdk/azcore v1.18.0 // indirect
@@ -0,0 +1,100 @@
+package format
+
+import (
+	"fmt"
+	"strings"
+)
+
+// partHeader is the continuation header of the notes split in several
+// messages, e.g. "_(part 2/3)_"
+const partHeader = "_(part %d/%d)_\n\n"
+
+// SplitMessage splits a text in messages of at most limit characters,
+// keeping the ### sections together when they fit in a message and breaking
+// between lines otherwise. Each message starts with a "part N/M" header when
+// there are several.
+func SplitMessage(text string, limit int) []string {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil
+	}
+	if len([]rune(text)) <= limit {
+		return []string{text}
+	}
+
+	// Leave room for the headers
+	limit -= len([]rune(fmt.Sprintf(partHeader, 999, 999)))
+
+	var messages []string
+	current := ""
+	for _, section := range splitSections(text) {
+		if len([]rune(current))+len([]rune(section)) <= limit {
+			current += section
+			continue
+		}
+		if message := strings.TrimSpace(current); message != "" {
+			messages = append(messages, message)
+		}
+		current = ""
+		if len([]rune(section)) <= limit {
+			current = section
+			continue
+		}
+		// Sections longer than a message are split between lines
+		chunks := splitLines(section, limit)
+		messages = append(messages, chunks[:len(chunks)-1]...)
+		current = chunks[len(chunks)-1] + "\n\n"
+	}
+	if message := strings.TrimSpace(current); message != "" {
+		messages = append(messages, message)
+	}
+
+	for i := range messages {
+		messages[i] = fmt.Sprintf(partHeader, i+1, len(messages)) + messages[i]
+	}
+	return messages
+}
+
+// splitSections splits a Markdown text before each ### heading
+func splitSections(text string) []string {
+	var sections []string
+	current := ""
+	for _, line := range strings.SplitAfter(text, "\n") {
+		if strings.HasPrefix(line, "### ") && strings.TrimSpace(current) != "" {
+			sections = append(sections, current)
+			current = ""
+		}
+		current += line
+	}
+	return append(sections, current)
+}
+
+// splitLines splits a text in chunks of at most limit characters, breaking
+// between lines when possible
+func splitLines(text string, limit int) []string {
+	var chunks []string
+	var current []rune
+	flush := func() {
+		if chunk := strings.TrimSpace(string(current)); chunk != "" {
+			chunks = append(chunks, chunk)
+		}
+		current = current[:0]
+	}
+
+	for _, line := range strings.SplitAfter(text, "\n") {
+		runes := []rune(line)
+		if len(current)+len(runes) > limit {
+			flush()
+		}
+		// Lines longer than a chunk are cut
+		for len(runes) > limit {
+			current = append(current, runes[:limit]...)
+			flush()
+			runes = runes[limit:]
+		}
+		current = append(current, runes...)
+	}
+	flush()
+
+	return chunks
+}
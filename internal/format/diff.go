@@ -0,0 +1,49 @@
+// Package format holds the renderer helpers of the release notes that don't
+// depend on the tool's flags: the plain text entries, the diffs shown to the
+// reviewers, the stats of the sections, the HTML anchors and the splitting of
+// the notes in chat messages.
+package format
+
+import "strings"
+
+// LineDiff returns a unified-style diff of two texts, line by line, with
+// removed lines prefixed by "-", added ones by "+" and unchanged ones by " "
+func LineDiff(oldText, newText string) string {
+	oldLines := strings.Split(oldText, "\n")
+	newLines := strings.Split(newText, "\n")
+
+	// lcs[i][j] is the length of the longest common subsequence of
+	// oldLines[i:] and newLines[j:]
+	lcs := make([][]int, len(oldLines)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(newLines)+1)
+	}
+	for i := len(oldLines) - 1; i >= 0; i-- {
+		for j := len(newLines) - 1; j >= 0; j-- {
+			if oldLines[i] == newLines[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var diff strings.Builder
+	i, j := 0, 0
+	for i < len(oldLines) || j < len(newLines) {
+		switch {
+		case i < len(oldLines) && j < len(newLines) && oldLines[i] == newLines[j]:
+			diff.WriteString(" " + oldLines[i] + "\n")
+			i++
+			j++
+		case j < len(newLines) && (i == len(oldLines) || lcs[i][j+1] >= lcs[i+1][j]):
+			diff.WriteString("+" + newLines[j] + "\n")
+			j++
+		default:
+			diff.WriteString("-" + oldLines[i] + "\n")
+			i++
+		}
+	}
+
+	return diff.String()
+}
@@ -0,0 +1,14 @@
+package format
+
+import (
+	"regexp"
+	"strings"
+)
+
+// anchorRegexp matches the runs of characters left out of the anchors
+var anchorRegexp = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Anchor returns the HTML anchor of a section, e.g. bug-fixes for Bug Fixes
+func Anchor(title string) string {
+	return strings.Trim(anchorRegexp.ReplaceAllString(strings.ToLower(title), "-"), "-")
+}
@@ -0,0 +1,12 @@
+package format
+
+import "strings"
+
+// MarkdownBullet returns the text of a multi-line note, e.g. with the notes
+// of several release-note blocks, to be listed in a Markdown bullet. The
+// lines are kept inside the bullet, ending in two spaces so they are
+// rendered as line breaks by GitHub and Mattermost instead of being merged
+// into one sentence.
+func MarkdownBullet(note string) string {
+	return strings.ReplaceAll(note, "\n", "  \n  ")
+}
@@ -0,0 +1,44 @@
+package format
+
+import (
+	"fmt"
+	"strings"
+)
+
+// wordsPerMinute is the reading speed used to estimate the reading time
+const wordsPerMinute = 200
+
+// SectionStats summarizes the length of a section of the notes, to help
+// editors balance the sections and spot bloated ones
+type SectionStats struct {
+	Entries int
+	Words   int
+}
+
+// NotesStats returns the stats of a section listing the given notes
+func NotesStats(notes []string) SectionStats {
+	stats := SectionStats{Entries: len(notes)}
+	for _, note := range notes {
+		stats.Words += len(strings.Fields(note))
+	}
+	return stats
+}
+
+// ReadingMinutes is the estimated reading time, rounded up
+func (s SectionStats) ReadingMinutes() int {
+	return (s.Words + wordsPerMinute - 1) / wordsPerMinute
+}
+
+func (s SectionStats) String() string {
+	entries := "entries"
+	if s.Entries == 1 {
+		entries = "entry"
+	}
+
+	reading := "under a minute"
+	if minutes := s.ReadingMinutes(); minutes > 1 {
+		reading = fmt.Sprintf("about %d minutes", minutes)
+	}
+
+	return fmt.Sprintf("%d %s, %d words, %s to read", s.Entries, entries, s.Words, reading)
+}
@@ -0,0 +1,55 @@
+package format
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// TextEntry is a change listed in the plain text format of the notes
+type TextEntry struct {
+	// Label identifies the change, e.g. "PR #123 (+ #124)"
+	Label string
+	// Title is the title of the PR, followed by its markers if any
+	Title string
+	Note  string
+	// History tells when the note was last touched, listed when set
+	History string
+	// TestSteps are listed in the QA format, when set
+	TestSteps string
+}
+
+// WriteTextHeader writes the first line of the plain text notes of a
+// milestone
+func WriteTextHeader(w io.Writer, milestone string) {
+	fmt.Fprintf(w, "PRs with release notes in milestone %s:\n\n", milestone)
+}
+
+// WriteTextSection writes the heading of a section of the plain text notes,
+// e.g. a type of change
+func WriteTextSection(w io.Writer, title string) {
+	fmt.Fprintf(w, "%s:\n\n", title)
+}
+
+// WriteTextEntry writes a change and its release note in the plain text
+// format, followed by a blank line
+func WriteTextEntry(w io.Writer, entry TextEntry) {
+	fmt.Fprintf(w, "%s: %s\n", entry.Label, entry.Title)
+	fmt.Fprintf(w, "Release Note: %s\n", entry.Note)
+	if entry.History != "" {
+		fmt.Fprintf(w, "Note History: %s\n", entry.History)
+	}
+	if entry.TestSteps != "" {
+		fmt.Fprintf(w, "Test Steps:\n%s\n", indent(entry.TestSteps, "  "))
+	}
+	fmt.Fprintln(w)
+}
+
+// indent prefixes every line of text with prefix
+func indent(text string, prefix string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = prefix + line
+	}
+	return strings.Join(lines, "\n")
+}